@@ -9,6 +9,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// principalKey is the context key for the Principal set by RequireAuth
+type principalKey struct{}
+
+// Principal is the authenticated caller, derived from a token's claims
+type Principal struct {
+	UserID string
+}
+
 type AuthMiddleware struct {
 	secret string
 	logger *logrus.Logger
@@ -55,8 +63,7 @@ func (a *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), "user_id", userID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), &Principal{UserID: userID})))
 		} else {
 			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
 			return
@@ -64,7 +71,24 @@ func (a *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 	})
 }
 
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal set by RequireAuth, if any
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// GetUserIDFromContext returns the user ID of the principal set by RequireAuth, if any
+//
+// Deprecated: Use PrincipalFrom.
 func GetUserIDFromContext(ctx context.Context) (string, bool) {
-	userID, ok := ctx.Value("user_id").(string)
-	return userID, ok
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return "", false
+	}
+	return principal.UserID, true
 }
@@ -1,5 +1,28 @@
 package auth
 
-func IssueJWT() {
+import (
+	"errors"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IssueJWT signs an HS256 token for the user, valid for ttl, that RequireAuth accepts with the same secret
+// Generated services issue tokens with auth.Issuer instead, which adds signing key rotation, refresh tokens and a JWKS endpoint
+func IssueJWT(secret, userID string, ttl time.Duration) (string, error) {
+	if secret == "" {
+		return "", errors.New("secret is required")
+	}
+	if userID == "" {
+		return "", errors.New("user ID is required")
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":     userID,
+		"user_id": userID,
+		"iat":     now.Unix(),
+		"exp":     now.Add(ttl).Unix(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
 }
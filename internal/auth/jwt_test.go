@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueJWTRoundTrip(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	middleware := NewAuthMiddleware("test-secret", logger)
+
+	var gotUserID string
+	handler := middleware.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := PrincipalFrom(r.Context()); ok {
+			gotUserID = principal.UserID
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("issued token is accepted", func(t *testing.T) {
+		token, err := IssueJWT("test-secret", "user-1", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, serve(token))
+		assert.Equal(t, "user-1", gotUserID)
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		token, err := IssueJWT("test-secret", "user-1", -time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, serve(token))
+	})
+
+	t.Run("token signed with another secret is rejected", func(t *testing.T) {
+		token, err := IssueJWT("other-secret", "user-1", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, serve(token))
+	})
+
+	t.Run("missing secret or user ID is an error", func(t *testing.T) {
+		_, err := IssueJWT("", "user-1", time.Minute)
+		assert.Error(t, err)
+		_, err = IssueJWT("test-secret", "", time.Minute)
+		assert.Error(t, err)
+	})
+}
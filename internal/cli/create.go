@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/anmho/create-go-service/internal/generator"
@@ -10,6 +11,9 @@ import (
 	"github.com/anmho/create-go-service/internal/generator/config"
 	"github.com/anmho/create-go-service/internal/generator/database"
 	"github.com/anmho/create-go-service/internal/generator/deployment"
+	"github.com/anmho/create-go-service/internal/generator/events"
+	"github.com/anmho/create-go-service/internal/generator/jobs"
+	"github.com/anmho/create-go-service/internal/generator/ratelimit"
 	"github.com/anmho/create-go-service/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -36,6 +40,13 @@ func Execute() error {
 		posthogAPIKey  string
 		posthogHost    string
 		deploymentType string
+		fromOpenAPI    string
+		fromProto      string
+		pgCodegen      string
+		dynamoDesign   string
+		eventBroker    string
+		jobQueue       string
+		rateLimitStore string
 	)
 
 	rootCmd := &cobra.Command{
@@ -47,11 +58,12 @@ func Execute() error {
 			flagsProvided := projectName != "" || modulePath != "" || outputDir != "" ||
 				apiType != "" || databaseType != "" || features != "" ||
 				jwtSecret != "" || posthogAPIKey != "" || posthogHost != "" ||
-				deploymentType != ""
+				deploymentType != "" || fromOpenAPI != "" || fromProto != "" || pgCodegen != "" ||
+				dynamoDesign != "" || eventBroker != "" || jobQueue != "" || rateLimitStore != ""
 
 			// If flags provided, use direct mode
 			if flagsProvided {
-				return generateDirect(projectName, modulePath, outputDir, apiType, databaseType, features, jwtSecret, posthogAPIKey, posthogHost, deploymentType)
+				return generateDirect(projectName, modulePath, outputDir, apiType, databaseType, features, jwtSecret, posthogAPIKey, posthogHost, deploymentType, fromOpenAPI, fromProto, pgCodegen, dynamoDesign, eventBroker, jobQueue, rateLimitStore)
 			}
 
 			// Otherwise, use TUI
@@ -77,12 +89,19 @@ func Execute() error {
 	rootCmd.Flags().StringVar(&modulePath, "module-path", "", "Go module path (e.g., github.com/user/project)")
 	rootCmd.Flags().StringVar(&outputDir, "output-dir", "", "Output directory (default: ./<project-name>)")
 	rootCmd.Flags().StringVar(&apiType, "api", "", "API type: chi, grpc, or huma")
-	rootCmd.Flags().StringVar(&databaseType, "database", "", "Database type: dynamodb or postgres")
-	rootCmd.Flags().StringVar(&features, "features", "", "Comma-separated features: auth,posthog")
+	rootCmd.Flags().StringVar(&databaseType, "database", "", "Database type: dynamodb, postgres, mysql, mongodb, or sqlite")
+	rootCmd.Flags().StringVar(&features, "features", "", "Comma-separated features: auth,posthog,soft-delete,outbox,jobs,scheduler,apikeys,tokens,tracing,observability")
 	rootCmd.Flags().StringVar(&jwtSecret, "jwt-secret", "", "JWT secret (required if auth feature is enabled)")
 	rootCmd.Flags().StringVar(&posthogAPIKey, "posthog-api-key", "", "PostHog API key (required if posthog feature is enabled)")
 	rootCmd.Flags().StringVar(&posthogHost, "posthog-host", "", "PostHog host (required if posthog feature is enabled)")
 	rootCmd.Flags().StringVar(&deploymentType, "deployment", "", "Deployment type: fly")
+	rootCmd.Flags().StringVar(&fromOpenAPI, "from-openapi", "", "Generate Chi handlers from an OpenAPI 3 spec (YAML or JSON)")
+	rootCmd.Flags().StringVar(&fromProto, "from-proto", "", "Scaffold gRPC handler stubs for every service in an existing .proto directory")
+	rootCmd.Flags().StringVar(&pgCodegen, "postgres-codegen", "", "Postgres query layer: sqlc (default: hand-written queries)")
+	rootCmd.Flags().StringVar(&dynamoDesign, "dynamodb-design", "", "DynamoDB table layout: single-table (default: one table per entity)")
+	rootCmd.Flags().StringVar(&eventBroker, "events", "", "Message broker for outbox events: nats, kafka, sqs, or pubsub (default: log events)")
+	rootCmd.Flags().StringVar(&jobQueue, "jobs-queue", "", "Store for background jobs: postgres or redis (default: postgres with --database postgres, otherwise redis)")
+	rootCmd.Flags().StringVar(&rateLimitStore, "rate-limit-store", "", "Store for API key rate limits: memory or redis (default: memory)")
 
 	// Add version flag
 	rootCmd.Flags().BoolP("version", "v", false, "Show version information")
@@ -101,7 +120,7 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
-func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, features, jwtSecret, posthogAPIKey, posthogHost, deploymentType string) error {
+func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, features, jwtSecret, posthogAPIKey, posthogHost, deploymentType, fromOpenAPI, fromProto, pgCodegen, dynamoDesign, eventBroker, jobQueue, rateLimitStore string) error {
 	// Validate required fields
 	if projectName == "" {
 		return fmt.Errorf("--project-name is required")
@@ -113,7 +132,7 @@ func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, f
 		return fmt.Errorf("--api is required (chi, grpc, or huma)")
 	}
 	if databaseType == "" {
-		return fmt.Errorf("--database is required (dynamodb or postgres)")
+		return fmt.Errorf("--database is required (dynamodb, postgres, mysql, mongodb, or sqlite)")
 	}
 
 	// Set default output directory
@@ -134,6 +153,13 @@ func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, f
 		return fmt.Errorf("invalid API type: %s (must be chi, grpc, or huma)", apiType)
 	}
 
+	if fromOpenAPI != "" && apiTypes[0] != api.TypeChi {
+		return fmt.Errorf("--from-openapi requires --api chi")
+	}
+	if fromProto != "" && apiTypes[0] != api.TypeGRPC {
+		return fmt.Errorf("--from-proto requires --api grpc")
+	}
+
 	// Parse database type
 	var dbType database.Type
 	switch strings.ToLower(databaseType) {
@@ -141,8 +167,42 @@ func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, f
 		dbType = database.TypeDynamoDB
 	case "postgres":
 		dbType = database.TypePostgres
+	case "mysql":
+		dbType = database.TypeMySQL
+	case "mongodb":
+		dbType = database.TypeMongoDB
+	case "sqlite":
+		dbType = database.TypeSQLite
+	default:
+		return fmt.Errorf("invalid database type: %s (must be dynamodb, postgres, mysql, mongodb, or sqlite)", databaseType)
+	}
+
+	// Parse Postgres query codegen
+	var dbCodegen database.Codegen
+	switch strings.ToLower(pgCodegen) {
+	case "":
+		dbCodegen = database.CodegenNone
+	case "sqlc":
+		dbCodegen = database.CodegenSQLC
 	default:
-		return fmt.Errorf("invalid database type: %s (must be dynamodb or postgres)", databaseType)
+		return fmt.Errorf("invalid postgres codegen: %s (must be sqlc)", pgCodegen)
+	}
+	if dbCodegen != database.CodegenNone && dbType != database.TypePostgres {
+		return fmt.Errorf("--postgres-codegen requires --database postgres")
+	}
+
+	// Parse DynamoDB table design
+	var dbDesign database.Design
+	switch strings.ToLower(dynamoDesign) {
+	case "":
+		dbDesign = database.DesignTablePerEntity
+	case "single-table":
+		dbDesign = database.DesignSingleTable
+	default:
+		return fmt.Errorf("invalid dynamodb design: %s (must be single-table)", dynamoDesign)
+	}
+	if dbDesign != database.DesignTablePerEntity && dbType != database.TypeDynamoDB {
+		return fmt.Errorf("--dynamodb-design requires --database dynamodb")
 	}
 
 	// Parse features
@@ -155,12 +215,81 @@ func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, f
 				featureList = append(featureList, config.FeatureAuth)
 			case "posthog":
 				featureList = append(featureList, config.FeaturePostHog)
+			case "soft-delete":
+				featureList = append(featureList, config.FeatureSoftDelete)
+			case "outbox":
+				featureList = append(featureList, config.FeatureOutbox)
+			case "jobs":
+				featureList = append(featureList, config.FeatureJobs)
+			case "scheduler":
+				featureList = append(featureList, config.FeatureScheduler)
+			case "apikeys":
+				featureList = append(featureList, config.FeatureAPIKeys)
+			case "tokens":
+				featureList = append(featureList, config.FeatureTokens)
+			case "tracing":
+				featureList = append(featureList, config.FeatureTracing)
+			case "observability":
+				featureList = append(featureList, config.FeatureObservability)
 			default:
-				return fmt.Errorf("invalid feature: %s (must be auth or posthog)", f)
+				return fmt.Errorf("invalid feature: %s (must be auth, posthog, soft-delete, outbox, jobs, scheduler, apikeys, tokens, tracing, or observability)", f)
 			}
 		}
 	}
 
+	// Parse event broker
+	var eventsType events.Type
+	switch strings.ToLower(eventBroker) {
+	case "":
+		eventsType = events.TypeNone
+	case "nats":
+		eventsType = events.TypeNATS
+	case "kafka":
+		eventsType = events.TypeKafka
+	case "sqs":
+		eventsType = events.TypeSQS
+	case "pubsub":
+		eventsType = events.TypePubSub
+	default:
+		return fmt.Errorf("invalid event broker: %s (must be nats, kafka, sqs, or pubsub)", eventBroker)
+	}
+	if eventsType != events.TypeNone && !slices.Contains(featureList, config.FeatureOutbox) {
+		return fmt.Errorf("--events requires --features outbox")
+	}
+
+	// Parse job queue store
+	var jobsType jobs.Type
+	switch strings.ToLower(jobQueue) {
+	case "":
+		jobsType = jobs.TypeNone
+	case "postgres":
+		jobsType = jobs.TypePostgres
+	case "redis":
+		jobsType = jobs.TypeRedis
+	default:
+		return fmt.Errorf("invalid job queue: %s (must be postgres or redis)", jobQueue)
+	}
+	if jobsType != jobs.TypeNone && !slices.Contains(featureList, config.FeatureJobs) {
+		return fmt.Errorf("--jobs-queue requires --features jobs")
+	}
+	if jobsType == jobs.TypePostgres && dbType != database.TypePostgres {
+		return fmt.Errorf("--jobs-queue postgres requires --database postgres")
+	}
+
+	// Parse rate limit store
+	var rateLimitType ratelimit.Type
+	switch strings.ToLower(rateLimitStore) {
+	case "", "memory":
+		rateLimitType = ratelimit.TypeMemory
+	case "redis":
+		rateLimitType = ratelimit.TypeRedis
+	default:
+		return fmt.Errorf("invalid rate limit store: %s (must be memory or redis)", rateLimitStore)
+	}
+	if rateLimitStore != "" && !slices.Contains(featureList, config.FeatureAPIKeys) {
+		return fmt.Errorf("--rate-limit-store requires --features apikeys")
+	}
+
 	// Validate feature requirements
 	for _, f := range featureList {
 		switch f {
@@ -168,6 +297,10 @@ func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, f
 			if jwtSecret == "" {
 				return fmt.Errorf("--jwt-secret is required when --features includes auth")
 			}
+		case config.FeatureTokens:
+			if !slices.Contains(featureList, config.FeatureAuth) {
+				return fmt.Errorf("--features tokens requires auth")
+			}
 		case config.FeaturePostHog:
 			if posthogAPIKey == "" {
 				return fmt.Errorf("--posthog-api-key is required when --features includes posthog")
@@ -204,14 +337,27 @@ func generateDirect(projectName, modulePath, outputDir, apiType, databaseType, f
 			Host:   posthogHost,
 		},
 		API: api.Config{
-			Types: apiTypes,
+			Types:       apiTypes,
+			OpenAPISpec: fromOpenAPI,
+			ProtoDir:    fromProto,
 		},
 		Database: database.Config{
-			Type: dbType,
+			Type:    dbType,
+			Codegen: dbCodegen,
+			Design:  dbDesign,
 		},
 		Deployment: deployment.Config{
 			Type: depType,
 		},
+		Events: events.Config{
+			Type: eventsType,
+		},
+		Jobs: jobs.Config{
+			Type: jobsType,
+		},
+		RateLimit: ratelimit.Config{
+			Type: rateLimitType,
+		},
 	}
 
 	// Generate project
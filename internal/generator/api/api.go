@@ -11,5 +11,7 @@ const (
 
 // Config holds API-related configuration
 type Config struct {
-	Types []Type // API types to generate (chi, grpc, huma)
+	Types       []Type // API types to generate (chi, grpc, huma)
+	OpenAPISpec string // Path to an OpenAPI 3 document to generate Chi handlers from (optional)
+	ProtoDir    string // Path to an existing .proto tree to scaffold gRPC services from (optional)
 }
@@ -4,14 +4,25 @@ import (
 	"github.com/anmho/create-go-service/internal/generator/api"
 	"github.com/anmho/create-go-service/internal/generator/database"
 	"github.com/anmho/create-go-service/internal/generator/deployment"
+	"github.com/anmho/create-go-service/internal/generator/events"
+	"github.com/anmho/create-go-service/internal/generator/jobs"
+	"github.com/anmho/create-go-service/internal/generator/ratelimit"
 )
 
 // Feature represents an optional feature
 type Feature string
 
 const (
-	FeatureAuth    Feature = "auth"    // Optional: JWT authentication
-	FeaturePostHog Feature = "posthog" // Optional: PostHog event tracking
+	FeatureAuth          Feature = "auth"          // Optional: JWT authentication
+	FeaturePostHog       Feature = "posthog"       // Optional: PostHog event tracking
+	FeatureSoftDelete    Feature = "soft-delete"   // Optional: soft delete, restore and purge of posts
+	FeatureOutbox        Feature = "outbox"        // Optional: transactional outbox of post events with a relay worker
+	FeatureJobs          Feature = "jobs"          // Optional: background job queue with a worker, retries and dead-lettering
+	FeatureScheduler     Feature = "scheduler"     // Optional: cron scheduled tasks run by an elected leader instance
+	FeatureAPIKeys       Feature = "apikeys"       // Optional: hashed API keys authenticating X-API-Key, rate limited per key
+	FeatureTokens        Feature = "tokens"        // Optional: issues access and refresh tokens (requires auth)
+	FeatureTracing       Feature = "tracing"       // Optional: OpenTelemetry tracing exported over OTLP
+	FeatureObservability Feature = "observability" // Optional: Grafana dashboard, Prometheus alert rules and a compose profile running both
 	// Note: Metrics and hot reload are always enabled, not optional features
 )
 
@@ -30,6 +41,9 @@ type ProjectConfig struct {
 	API        api.Config
 	Database   database.Config
 	Deployment deployment.Config
+	Events     events.Config    // Only used with the outbox feature
+	Jobs       jobs.Config      // Only used with the jobs feature
+	RateLimit  ratelimit.Config // Only used with the apikeys feature
 }
 
 // AuthConfig holds authentication configuration
@@ -6,9 +6,30 @@ type Type string
 const (
 	TypeDynamoDB Type = "dynamodb"
 	TypePostgres Type = "postgres"
+	TypeMySQL    Type = "mysql"
+	TypeMongoDB  Type = "mongodb"
+	TypeSQLite   Type = "sqlite"
+)
+
+// Codegen represents how the query layer of a SQL database is written
+type Codegen string
+
+const (
+	CodegenNone Codegen = ""     // Hand-written queries
+	CodegenSQLC Codegen = "sqlc" // Queries compiled by sqlc (Postgres only)
+)
+
+// Design represents how DynamoDB tables are laid out
+type Design string
+
+const (
+	DesignTablePerEntity Design = ""             // One table per entity with named GSIs
+	DesignSingleTable    Design = "single-table" // One table with overloaded PK/SK/GSI1PK/GSI1SK keys
 )
 
 // Config holds database-related configuration
 type Config struct {
-	Type Type
+	Type    Type
+	Codegen Codegen
+	Design  Design
 }
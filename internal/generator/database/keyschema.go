@@ -0,0 +1,169 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// KeyFieldType is the Go type of a value referenced by a key pattern
+type KeyFieldType string
+
+const (
+	KeyFieldUUID KeyFieldType = "uuid" // uuid.UUID, written as its string form
+	KeyFieldTime KeyFieldType = "time" // time.Time, written as zero-padded epoch millis so keys sort chronologically
+)
+
+// KeyField is a value referenced by a key pattern
+type KeyField struct {
+	Name   string // e.g. "UserID"; the builder parameter is the lower camel case form
+	Type   KeyFieldType
+	Source string // field of the entity struct holding the value, e.g. "ID"
+}
+
+// KeySchema describes how an entity is stored in a single-table design
+// Each key is a pattern of literals and {Field} references, e.g. "USER#{UserID}"
+type KeySchema struct {
+	Entity string // prefixes the generated key builders, e.g. "Post" gives PostPK
+	Fields []KeyField
+	PK     string
+	SK     string
+	GSI1PK string
+	GSI1SK string
+}
+
+// KeyBuilder is a typed key builder function rendered into the generated project
+type KeyBuilder struct {
+	Name      string // e.g. "PostSK"
+	Attribute string // table attribute the key is stored in, e.g. "SK"
+	Pattern   string // key pattern from the schema
+	Prefix    string // literal text before the first field, for begins_with conditions
+	Params    string // Go parameter list, e.g. "createdAt time.Time, postID uuid.UUID"
+	Args      string // Go arguments taking the values from an entity, e.g. "post.CreatedAt, post.ID"
+	Expr      string // Go expression building the key
+}
+
+// PostKeySchema is the key schema of posts
+// Posts are partitioned by user and sorted by creation time; GSI1 looks them up by ID
+var PostKeySchema = KeySchema{
+	Entity: "Post",
+	Fields: []KeyField{
+		{Name: "UserID", Type: KeyFieldUUID, Source: "UserID"},
+		{Name: "PostID", Type: KeyFieldUUID, Source: "ID"},
+		{Name: "CreatedAt", Type: KeyFieldTime, Source: "CreatedAt"},
+	},
+	PK:     "USER#{UserID}",
+	SK:     "POST#{CreatedAt}#{PostID}",
+	GSI1PK: "POST#{PostID}",
+	GSI1SK: "POST#{PostID}",
+}
+
+// Builders returns the key builders of the schema in PK, SK, GSI1PK, GSI1SK order
+func (s KeySchema) Builders() ([]KeyBuilder, error) {
+	fields := make(map[string]KeyField, len(s.Fields))
+	for _, f := range s.Fields {
+		fields[f.Name] = f
+	}
+
+	keys := []struct{ attribute, pattern string }{
+		{"PK", s.PK},
+		{"SK", s.SK},
+		{"GSI1PK", s.GSI1PK},
+		{"GSI1SK", s.GSI1SK},
+	}
+
+	builders := make([]KeyBuilder, 0, len(keys))
+	for _, key := range keys {
+		b, err := buildKey(s.Entity+key.attribute, key.pattern, lowerCamel(s.Entity), fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s key schema: %s: %w", s.Entity, key.attribute, err)
+		}
+		b.Attribute = key.attribute
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+// buildKey parses a key pattern into a builder whose arguments are read from the entity variable
+func buildKey(name, pattern, entity string, fields map[string]KeyField) (KeyBuilder, error) {
+	if pattern == "" {
+		return KeyBuilder{}, fmt.Errorf("pattern is empty")
+	}
+
+	b := KeyBuilder{Name: name, Pattern: pattern}
+	var (
+		params []string
+		args   []string
+		parts  []string
+		seen   = make(map[string]bool)
+		rest   = pattern
+	)
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest))
+			break
+		}
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest[:start]))
+			if len(params) == 0 {
+				b.Prefix = rest[:start]
+			}
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return KeyBuilder{}, fmt.Errorf("unterminated field reference in %q", pattern)
+		}
+		fieldName := rest[start+1 : start+end]
+		field, ok := fields[fieldName]
+		if !ok {
+			return KeyBuilder{}, fmt.Errorf("unknown field %q in %q", fieldName, pattern)
+		}
+		rest = rest[start+end+1:]
+
+		param := lowerCamel(field.Name)
+		switch field.Type {
+		case KeyFieldUUID:
+			parts = append(parts, param+".String()")
+			if !seen[param] {
+				params = append(params, param+" uuid.UUID")
+				args = append(args, entity+"."+field.Source)
+			}
+		case KeyFieldTime:
+			parts = append(parts, "keyTime("+param+")")
+			if !seen[param] {
+				params = append(params, param+" time.Time")
+				args = append(args, entity+"."+field.Source)
+			}
+		default:
+			return KeyBuilder{}, fmt.Errorf("field %q has unsupported type %q", field.Name, field.Type)
+		}
+		seen[param] = true
+	}
+
+	if len(params) == 0 {
+		b.Prefix = pattern
+	}
+	b.Params = strings.Join(params, ", ")
+	b.Args = strings.Join(args, ", ")
+	b.Expr = strings.Join(parts, " + ")
+	return b, nil
+}
+
+// lowerCamel lowercases the leading word of an identifier, keeping initialisms intact (PostID -> postID, ID -> id)
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	if i > 1 && i < len(runes) {
+		// Initialism followed by a word: keep the word's leading capital
+		i--
+	}
+	for j := 0; j < i; j++ {
+		runes[j] = unicode.ToLower(runes[j])
+	}
+	return string(runes)
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPostKeySchemaBuilders(t *testing.T) {
+	builders, err := PostKeySchema.Builders()
+	if err != nil {
+		t.Fatalf("Builders() error = %v", err)
+	}
+
+	want := []KeyBuilder{
+		{Name: "PostPK", Attribute: "PK", Pattern: "USER#{UserID}", Prefix: "USER#", Params: "userID uuid.UUID", Args: "post.UserID", Expr: `"USER#" + userID.String()`},
+		{Name: "PostSK", Attribute: "SK", Pattern: "POST#{CreatedAt}#{PostID}", Prefix: "POST#", Params: "createdAt time.Time, postID uuid.UUID", Args: "post.CreatedAt, post.ID", Expr: `"POST#" + keyTime(createdAt) + "#" + postID.String()`},
+		{Name: "PostGSI1PK", Attribute: "GSI1PK", Pattern: "POST#{PostID}", Prefix: "POST#", Params: "postID uuid.UUID", Args: "post.ID", Expr: `"POST#" + postID.String()`},
+		{Name: "PostGSI1SK", Attribute: "GSI1SK", Pattern: "POST#{PostID}", Prefix: "POST#", Params: "postID uuid.UUID", Args: "post.ID", Expr: `"POST#" + postID.String()`},
+	}
+	if len(builders) != len(want) {
+		t.Fatalf("got %d builders, want %d", len(builders), len(want))
+	}
+	for i := range want {
+		if builders[i] != want[i] {
+			t.Errorf("builder %d = %+v, want %+v", i, builders[i], want[i])
+		}
+	}
+}
+
+func TestKeySchemaBuilders_Errors(t *testing.T) {
+	fields := []KeyField{{Name: "ID", Type: KeyFieldUUID}}
+	tests := []struct {
+		name   string
+		schema KeySchema
+		want   string
+	}{
+		{"empty pattern", KeySchema{Entity: "Tag", Fields: fields, PK: "TAG#{ID}", SK: "TAG", GSI1PK: "TAG"}, "GSI1SK: pattern is empty"},
+		{"unknown field", KeySchema{Entity: "Tag", Fields: fields, PK: "TAG#{Name}", SK: "TAG", GSI1PK: "TAG", GSI1SK: "TAG"}, `unknown field "Name"`},
+		{"unterminated", KeySchema{Entity: "Tag", Fields: fields, PK: "TAG#{ID", SK: "TAG", GSI1PK: "TAG", GSI1SK: "TAG"}, "unterminated field reference"},
+		{"unsupported type", KeySchema{Entity: "Tag", Fields: []KeyField{{Name: "ID", Type: "int"}}, PK: "TAG#{ID}", SK: "TAG", GSI1PK: "TAG", GSI1SK: "TAG"}, `unsupported type "int"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.schema.Builders()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Builders() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLowerCamel(t *testing.T) {
+	for in, want := range map[string]string{
+		"UserID":    "userID",
+		"CreatedAt": "createdAt",
+		"ID":        "id",
+		"URLPath":   "urlPath",
+	} {
+		if got := lowerCamel(in); got != want {
+			t.Errorf("lowerCamel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package events
+
+// Type represents the message broker outbox events are published to
+type Type string
+
+const (
+	TypeNone   Type = ""       // Events are logged by the relay instead of published
+	TypeNATS   Type = "nats"   // NATS JetStream
+	TypeKafka  Type = "kafka"  // Kafka or a Kafka-compatible broker (e.g., Redpanda)
+	TypeSQS    Type = "sqs"    // Amazon SQS
+	TypePubSub Type = "pubsub" // Google Cloud Pub/Sub
+)
+
+// Config holds event publishing configuration
+type Config struct {
+	Type Type
+}
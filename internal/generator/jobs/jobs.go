@@ -0,0 +1,15 @@
+package jobs
+
+// Type represents the store background jobs are queued in
+type Type string
+
+const (
+	TypeNone     Type = ""         // Chosen from the database: Postgres when it is the database, otherwise Redis
+	TypePostgres Type = "postgres" // Jobs table in the service's Postgres database, claimed with SKIP LOCKED
+	TypeRedis    Type = "redis"    // Sorted sets and hashes in Redis
+)
+
+// Config holds background job configuration
+type Config struct {
+	Type Type
+}
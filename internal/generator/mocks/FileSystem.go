@@ -61,6 +61,36 @@ func (_m *FileSystem) ReadFile(name string) ([]byte, error) {
 	return r0, r1
 }
 
+// ReadDir provides a mock function with given fields: name
+func (_m *FileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadDir")
+	}
+
+	var r0 []fs.DirEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]fs.DirEntry, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) []fs.DirEntry); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]fs.DirEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveAll provides a mock function with given fields: path
 func (_m *FileSystem) RemoveAll(path string) error {
 	ret := _m.Called(path)
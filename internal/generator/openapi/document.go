@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document is the subset of an OpenAPI 3 document used for code generation
+type document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       info                 `yaml:"info"`
+	Paths      orderedMap[pathItem] `yaml:"paths"`
+	Components components           `yaml:"components"`
+}
+
+type info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type components struct {
+	Schemas       orderedMap[*schema]      `yaml:"schemas"`
+	Parameters    orderedMap[*parameter]   `yaml:"parameters"`
+	RequestBodies orderedMap[*requestBody] `yaml:"requestBodies"`
+	Responses     orderedMap[*response]    `yaml:"responses"`
+}
+
+type pathItem struct {
+	Parameters []*parameter `yaml:"parameters"`
+	Get        *operation   `yaml:"get"`
+	Put        *operation   `yaml:"put"`
+	Post       *operation   `yaml:"post"`
+	Delete     *operation   `yaml:"delete"`
+	Options    *operation   `yaml:"options"`
+	Head       *operation   `yaml:"head"`
+	Patch      *operation   `yaml:"patch"`
+}
+
+type operation struct {
+	OperationID string                `yaml:"operationId"`
+	Summary     string                `yaml:"summary"`
+	Description string                `yaml:"description"`
+	Tags        []string              `yaml:"tags"`
+	Parameters  []*parameter          `yaml:"parameters"`
+	RequestBody *requestBody          `yaml:"requestBody"`
+	Responses   orderedMap[*response] `yaml:"responses"`
+}
+
+type parameter struct {
+	Ref         string  `yaml:"$ref"`
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Ref         string                 `yaml:"$ref"`
+	Description string                 `yaml:"description"`
+	Required    bool                   `yaml:"required"`
+	Content     orderedMap[*mediaType] `yaml:"content"`
+}
+
+type response struct {
+	Ref         string                 `yaml:"$ref"`
+	Description string                 `yaml:"description"`
+	Content     orderedMap[*mediaType] `yaml:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+type schema struct {
+	Ref                  string              `yaml:"$ref"`
+	Type                 string              `yaml:"type"`
+	Format               string              `yaml:"format"`
+	Description          string              `yaml:"description"`
+	Properties           orderedMap[*schema] `yaml:"properties"`
+	Required             []string            `yaml:"required"`
+	Items                *schema             `yaml:"items"`
+	AdditionalProperties any                 `yaml:"additionalProperties"`
+	AllOf                []*schema           `yaml:"allOf"`
+	OneOf                []*schema           `yaml:"oneOf"`
+	AnyOf                []*schema           `yaml:"anyOf"`
+	Enum                 []any               `yaml:"enum"`
+	MinLength            *int                `yaml:"minLength"`
+	MaxLength            *int                `yaml:"maxLength"`
+	Minimum              *float64            `yaml:"minimum"`
+	Maximum              *float64            `yaml:"maximum"`
+	MinItems             *int                `yaml:"minItems"`
+	MaxItems             *int                `yaml:"maxItems"`
+}
+
+// orderedMap is a YAML mapping that remembers the order of its keys,
+// so generated code follows the order of the spec instead of map iteration order
+type orderedMap[T any] struct {
+	keys   []string
+	values map[string]T
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (m *orderedMap[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+
+	m.values = make(map[string]T, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		var value T
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if _, exists := m.values[key]; !exists {
+			m.keys = append(m.keys, key)
+		}
+		m.values[key] = value
+	}
+	return nil
+}
+
+// Keys returns the keys in document order
+func (m orderedMap[T]) Keys() []string {
+	return m.keys
+}
+
+// Get returns the value stored under key
+func (m orderedMap[T]) Get(key string) (T, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Len returns the number of entries
+func (m orderedMap[T]) Len() int {
+	return len(m.keys)
+}
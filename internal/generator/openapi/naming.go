@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are written in all caps in Go identifiers (e.g. UserID, not UserId)
+var commonInitialisms = map[string]bool{
+	"API":  true,
+	"HTTP": true,
+	"ID":   true,
+	"IP":   true,
+	"JSON": true,
+	"SQL":  true,
+	"URI":  true,
+	"URL":  true,
+	"UUID": true,
+}
+
+// goName converts an identifier from the spec (snake_case, kebab-case, camelCase, paths)
+// into an exported Go identifier
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		upper := strings.ToUpper(word)
+		if commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		return "N" + name
+	}
+	return name
+}
+
+// splitWords splits s on non-alphanumeric characters and lower-to-upper case transitions
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	var prev rune
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+		prev = r
+	}
+	flush()
+
+	return words
+}
+
+// comment flattens a description into a single line suitable for a Go comment
+func comment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,833 @@
+// Package openapi converts an OpenAPI 3 document into the data used by the
+// openapi/*.go.tmpl templates: typed structs with validation, a server
+// interface per tag, and Chi routes for every operation.
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the code generation view of an OpenAPI document
+type Spec struct {
+	Title string
+	Types []*Type
+	Tags  []*Tag
+
+	// Import flags so templates only import what the generated code uses
+	NeedsErrors  bool // types.go: at least one validation check
+	NeedsFmt     bool // types.go: at least one nested Validate call
+	NeedsTime    bool // types.go: at least one date-time field
+	NeedsUTF8    bool // types.go: at least one string length check
+	NeedsStrconv bool // server.go: at least one non-string parameter
+	NeedsIO      bool // server.go: at least one optional request body
+}
+
+// Type is a generated Go type: a struct with a Validate method, or an alias
+type Type struct {
+	Name   string
+	Doc    []string // Comment lines
+	Alias  string   // Non-empty for non-object schemas (type Name = Alias)
+	Fields []*Field
+}
+
+// Field is a struct field of a generated type
+type Field struct {
+	Name        string
+	NamePad     string // Spaces aligning the field type like gofmt
+	Type        string
+	TypePad     string // Spaces aligning the struct tag like gofmt
+	JSON        string // JSON struct tag value
+	Description string
+	Checks      []Check
+	Nested      string // How to validate nested structs: "", "value", "pointer", or "slice"
+	NestedError string // Quoted fmt format used to wrap nested validation errors
+}
+
+// Check is a single validation rule: when Cond is true, validation fails with Message
+type Check struct {
+	Cond    string
+	Message string // Quoted Go string literal
+}
+
+// Tag groups the operations that share an OpenAPI tag into one server interface
+type Tag struct {
+	Name       string
+	Interface  string
+	Operations []*Operation
+}
+
+// Operation is a single path + method from the spec
+type Operation struct {
+	Name         string
+	Method       string // Chi router method (Get, Post, ...)
+	HTTPMethod   string // GET, POST, ...
+	Path         string
+	Summary      string
+	ParamsType   string
+	Params       []*Param
+	HasBody      bool
+	BodyType     string
+	BodyRequired bool
+	ResponseType string // Empty when the success response has no body
+	Status       string // Go expression for the success status code
+}
+
+// Param is a path, query, or header parameter decoded from the request
+type Param struct {
+	Field          string
+	Name           string
+	In             string
+	Required       bool
+	Source         string // Go expression returning the raw string value
+	Parse          string // Go expression parsing raw, empty for strings
+	Convert        string // Go expression converting the parsed value v to the field type
+	MissingMessage string // Quoted Go string literal
+	InvalidMessage string // Quoted Go string literal
+}
+
+// Parse parses an OpenAPI 3 document (YAML or JSON) into a Spec
+func Parse(data []byte) (*Spec, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q (must be 3.x)", doc.OpenAPI)
+	}
+
+	c := &converter{
+		doc:        &doc,
+		spec:       &Spec{Title: doc.Info.Title},
+		types:      make(map[string]bool),
+		operations: make(map[string]bool),
+		inline:     make(map[*schema]string),
+		schemas:    make(map[string]string),
+	}
+	for _, name := range templateIdentifiers {
+		c.types[name] = true
+	}
+	if err := c.convertComponents(); err != nil {
+		return nil, err
+	}
+	if err := c.convertPaths(); err != nil {
+		return nil, err
+	}
+	if len(c.operations) == 0 {
+		return nil, fmt.Errorf("OpenAPI document defines no operations")
+	}
+	c.setImportFlags()
+	c.alignFields()
+
+	return c.spec, nil
+}
+
+// converter holds the state used while converting a document into a Spec
+type converter struct {
+	doc        *document
+	spec       *Spec
+	types      map[string]bool
+	operations map[string]bool
+	inline     map[*schema]string // Struct types already generated for inline schemas
+	schemas    map[string]string  // Type names of components/schemas by key
+}
+
+// templateIdentifiers lists the exported identifiers the openapi templates declare in the generated package,
+// which generated types can't be named
+var templateIdentifiers = []string{"API", "ErrNotImplemented", "Error", "NewServer", "RegisterRoutes", "Server"}
+
+// httpMethods lists the supported operations of a path item in generation order
+var httpMethods = []struct {
+	name string
+	get  func(pathItem) *operation
+}{
+	{http.MethodGet, func(p pathItem) *operation { return p.Get }},
+	{http.MethodPost, func(p pathItem) *operation { return p.Post }},
+	{http.MethodPut, func(p pathItem) *operation { return p.Put }},
+	{http.MethodPatch, func(p pathItem) *operation { return p.Patch }},
+	{http.MethodDelete, func(p pathItem) *operation { return p.Delete }},
+	{http.MethodHead, func(p pathItem) *operation { return p.Head }},
+	{http.MethodOptions, func(p pathItem) *operation { return p.Options }},
+}
+
+// statusConstants maps common success status codes to their net/http constants
+var statusConstants = map[string]string{
+	"200": "http.StatusOK",
+	"201": "http.StatusCreated",
+	"202": "http.StatusAccepted",
+	"204": "http.StatusNoContent",
+}
+
+func (c *converter) convertComponents() error {
+	// Name every schema first, so $refs to schemas later in the document get the same name
+	// Schemas named like an identifier the templates declare (e.g., Error) get a Schema suffix
+	for _, key := range c.doc.Components.Schemas.Keys() {
+		name := goName(key)
+		if slices.Contains(templateIdentifiers, name) {
+			name += "Schema"
+		}
+		c.schemas[key] = name
+	}
+
+	for _, key := range c.doc.Components.Schemas.Keys() {
+		s, _ := c.doc.Components.Schemas.Get(key)
+		if s == nil {
+			continue
+		}
+		name := c.schemas[key]
+		doc := []string{fmt.Sprintf("%s is generated from components/schemas/%s", name, key)}
+		if s.Description != "" {
+			doc = append(doc, comment(s.Description))
+		}
+
+		if c.isObject(s) {
+			if _, err := c.addStruct(name, doc, s); err != nil {
+				return err
+			}
+			continue
+		}
+
+		alias, err := c.goType(s, name, true)
+		if err != nil {
+			return fmt.Errorf("components/schemas/%s: %w", key, err)
+		}
+		if err := c.reserveType(name); err != nil {
+			return err
+		}
+		c.spec.Types = append(c.spec.Types, &Type{Name: name, Doc: doc, Alias: alias})
+	}
+	return nil
+}
+
+func (c *converter) convertPaths() error {
+	for _, path := range c.doc.Paths.Keys() {
+		item, _ := c.doc.Paths.Get(path)
+		for _, method := range httpMethods {
+			op := method.get(item)
+			if op == nil {
+				continue
+			}
+			o, err := c.convertOperation(path, method.name, item.Parameters, op)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", method.name, path, err)
+			}
+
+			tagName := "default"
+			if len(op.Tags) > 0 {
+				tagName = op.Tags[0]
+			}
+			c.tag(tagName).Operations = append(c.tag(tagName).Operations, o)
+		}
+	}
+	return nil
+}
+
+// tag returns the Tag with the given name, creating it on first use
+func (c *converter) tag(name string) *Tag {
+	for _, t := range c.spec.Tags {
+		if t.Name == name {
+			return t
+		}
+	}
+	t := &Tag{Name: name, Interface: goName(name) + "API"}
+	c.spec.Tags = append(c.spec.Tags, t)
+	return t
+}
+
+func (c *converter) convertOperation(path, method string, shared []*parameter, op *operation) (*Operation, error) {
+	id := op.OperationID
+	if id == "" {
+		id = strings.ToLower(method) + " " + path
+	}
+	name := goName(id)
+	if c.operations[name] {
+		return nil, fmt.Errorf("duplicate operation name %s", name)
+	}
+	c.operations[name] = true
+
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Description
+	}
+	o := &Operation{
+		Name:       name,
+		Method:     goName(strings.ToLower(method)),
+		HTTPMethod: method,
+		Path:       path,
+		Summary:    comment(summary),
+		ParamsType: name + "Params",
+	}
+
+	paramsType := &Type{
+		Name: o.ParamsType,
+		Doc:  []string{fmt.Sprintf("%s holds the decoded parameters and body of %s", o.ParamsType, name)},
+	}
+	if err := c.reserveType(paramsType.Name); err != nil {
+		return nil, err
+	}
+	c.spec.Types = append(c.spec.Types, paramsType)
+
+	params, err := c.mergeParameters(shared, op.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range params {
+		param, field, err := c.convertParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		o.Params = append(o.Params, param)
+		paramsType.Fields = append(paramsType.Fields, field)
+	}
+
+	if op.RequestBody != nil {
+		body, err := c.resolveRequestBody(op.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		if s := jsonSchema(body.Content); s != nil {
+			bodyType, err := c.goType(s, name+"Body", false)
+			if err != nil {
+				return nil, fmt.Errorf("request body: %w", err)
+			}
+			o.HasBody = true
+			o.BodyType = bodyType
+			o.BodyRequired = body.Required
+			paramsType.Fields = append(paramsType.Fields, &Field{
+				Name:        "Body",
+				Type:        bodyType,
+				JSON:        "-",
+				Description: comment(body.Description),
+				Checks:      c.checks("v.Body", "body", c.resolve(s), false),
+				Nested:      c.nestedMode(s, true),
+				NestedError: strconv.Quote("body: %w"),
+			})
+		}
+	}
+
+	o.Status = "http.StatusOK"
+	for _, code := range op.Responses.Keys() {
+		if len(code) != 3 || code[0] != '2' {
+			continue
+		}
+		if constant, ok := statusConstants[code]; ok {
+			o.Status = constant
+		} else {
+			o.Status = code
+		}
+
+		resp, _ := op.Responses.Get(code)
+		resp, err := c.resolveResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+		if s := jsonSchema(resp.Content); s != nil && code != "204" {
+			respType, err := c.goType(s, name+"Response", false)
+			if err != nil {
+				return nil, fmt.Errorf("response %s: %w", code, err)
+			}
+			if !strings.HasPrefix(respType, "[]") && !strings.HasPrefix(respType, "map[") && respType != "any" {
+				respType = "*" + respType
+			}
+			o.ResponseType = respType
+		}
+		break
+	}
+
+	return o, nil
+}
+
+// mergeParameters combines path-level and operation-level parameters;
+// operation-level parameters override path-level ones with the same name and location
+func (c *converter) mergeParameters(shared, own []*parameter) ([]*parameter, error) {
+	var merged []*parameter
+	index := make(map[string]int)
+	for _, list := range [][]*parameter{shared, own} {
+		for _, p := range list {
+			resolved, err := c.resolveParameter(p)
+			if err != nil {
+				return nil, err
+			}
+			key := resolved.In + ":" + resolved.Name
+			if i, ok := index[key]; ok {
+				merged[i] = resolved
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, resolved)
+		}
+	}
+	return merged, nil
+}
+
+func (c *converter) convertParameter(p *parameter) (*Param, *Field, error) {
+	param := &Param{
+		Field:          goName(p.Name),
+		Name:           p.Name,
+		In:             p.In,
+		Required:       p.Required || p.In == "path",
+		MissingMessage: strconv.Quote(fmt.Sprintf("Missing %s parameter %s", p.In, p.Name)),
+		InvalidMessage: strconv.Quote(fmt.Sprintf("Invalid %s parameter %s", p.In, p.Name)),
+	}
+	switch p.In {
+	case "path":
+		param.Source = fmt.Sprintf("chi.URLParam(r, %s)", strconv.Quote(p.Name))
+	case "query":
+		param.Source = fmt.Sprintf("r.URL.Query().Get(%s)", strconv.Quote(p.Name))
+	case "header":
+		param.Source = fmt.Sprintf("r.Header.Get(%s)", strconv.Quote(p.Name))
+	default:
+		return nil, nil, fmt.Errorf("parameter %s: unsupported location %q (must be path, query, or header)", p.Name, p.In)
+	}
+
+	s := c.resolve(p.Schema)
+	goType := "string"
+	if s != nil {
+		switch s.Type {
+		case "integer":
+			switch s.Format {
+			case "int32":
+				goType, param.Parse, param.Convert = "int32", "strconv.ParseInt(raw, 10, 32)", "int32(v)"
+			case "int64":
+				goType, param.Parse, param.Convert = "int64", "strconv.ParseInt(raw, 10, 64)", "v"
+			default:
+				goType, param.Parse, param.Convert = "int", "strconv.Atoi(raw)", "v"
+			}
+		case "number":
+			if s.Format == "float" {
+				goType, param.Parse, param.Convert = "float32", "strconv.ParseFloat(raw, 32)", "float32(v)"
+			} else {
+				goType, param.Parse, param.Convert = "float64", "strconv.ParseFloat(raw, 64)", "v"
+			}
+		case "boolean":
+			goType, param.Parse, param.Convert = "bool", "strconv.ParseBool(raw)", "v"
+		}
+	}
+
+	field := &Field{
+		Name:        param.Field,
+		Type:        goType,
+		JSON:        "-",
+		Description: comment(p.Description),
+		// Presence of required parameters is enforced while decoding the request
+		Checks: c.checks("v."+param.Field, p.Name, s, false),
+	}
+	return param, field, nil
+}
+
+// addStruct registers a struct type for an object schema and converts its properties
+func (c *converter) addStruct(name string, doc []string, s *schema) (string, error) {
+	if err := c.reserveType(name); err != nil {
+		return "", err
+	}
+	t := &Type{Name: name, Doc: doc}
+	c.spec.Types = append(c.spec.Types, t)
+
+	keys, props, required, err := c.collectProperties(s)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	for _, key := range keys {
+		field, err := c.convertProperty(name, key, props[key], required[key])
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", name, key, err)
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	return name, nil
+}
+
+// collectProperties returns the properties of an object schema, merging allOf members
+func (c *converter) collectProperties(s *schema) ([]string, map[string]*schema, map[string]bool, error) {
+	var keys []string
+	props := make(map[string]*schema)
+	required := make(map[string]bool)
+
+	var collect func(s *schema) error
+	collect = func(s *schema) error {
+		if s.Ref != "" {
+			resolved, err := c.lookupSchema(s.Ref)
+			if err != nil {
+				return err
+			}
+			s = resolved
+		}
+		for _, member := range s.AllOf {
+			if err := collect(member); err != nil {
+				return err
+			}
+		}
+		for _, key := range s.Properties.Keys() {
+			if _, exists := props[key]; !exists {
+				keys = append(keys, key)
+			}
+			props[key], _ = s.Properties.Get(key)
+		}
+		for _, key := range s.Required {
+			required[key] = true
+		}
+		return nil
+	}
+
+	if err := collect(s); err != nil {
+		return nil, nil, nil, err
+	}
+	return keys, props, required, nil
+}
+
+func (c *converter) convertProperty(parent, key string, s *schema, required bool) (*Field, error) {
+	name := goName(key)
+	goType, err := c.goType(s, parent+name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	field := &Field{
+		Name:        name,
+		Type:        goType,
+		JSON:        key,
+		Checks:      c.checks("v."+name, key, c.resolve(s), required),
+		Nested:      c.nestedMode(s, required),
+		NestedError: strconv.Quote(key + ": %w"),
+	}
+	if s != nil {
+		field.Description = comment(s.Description)
+	}
+	if !required {
+		field.JSON += ",omitempty"
+	}
+	// Optional nested objects are pointers so they can be omitted
+	if field.Nested == "pointer" {
+		field.Type = "*" + goType
+	}
+	return field, nil
+}
+
+// goType returns the Go type for a schema, registering struct types for inline objects.
+// inStruct controls whether date-time strings become time.Time (struct fields only).
+func (c *converter) goType(s *schema, hint string, inStruct bool) (string, error) {
+	if s == nil {
+		return "any", nil
+	}
+	if s.Ref != "" {
+		if _, err := c.lookupSchema(s.Ref); err != nil {
+			return "", err
+		}
+		return c.schemas[refName(s.Ref)], nil
+	}
+	if len(s.AllOf) == 1 && s.Properties.Len() == 0 {
+		return c.goType(s.AllOf[0], hint, inStruct)
+	}
+	if c.isObject(s) {
+		// Schemas shared through allOf are generated once
+		if name, ok := c.inline[s]; ok {
+			return name, nil
+		}
+		c.inline[s] = hint
+		return c.addStruct(hint, []string{fmt.Sprintf("%s is an inline object from the OpenAPI spec", hint)}, s)
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "any", nil
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" && inStruct {
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32", nil
+		case "int64":
+			return "int64", nil
+		}
+		return "int", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		item, err := c.goType(s.Items, hint+"Item", inStruct)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object":
+		return "map[string]any", nil
+	}
+	return "any", nil
+}
+
+// checks returns the validation rules declared on a (resolved) schema.
+// expr is the Go expression for the value and name is its name in error messages.
+func (c *converter) checks(expr, name string, s *schema, required bool) []Check {
+	if s == nil {
+		return nil
+	}
+
+	var checks []Check
+	add := func(cond, format string, args ...any) {
+		checks = append(checks, Check{
+			Cond:    cond,
+			Message: strconv.Quote(name + " " + fmt.Sprintf(format, args...)),
+		})
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			break
+		}
+		// Optional strings are only checked when set
+		guard := expr + ` != "" && `
+		if required {
+			add(expr+` == ""`, "is required")
+			guard = ""
+		}
+		// JSON Schema lengths count characters, not bytes
+		if s.MinLength != nil && *s.MinLength > 0 {
+			add(fmt.Sprintf("%sutf8.RuneCountInString(%s) < %d", guard, expr, *s.MinLength), "must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil {
+			add(fmt.Sprintf("utf8.RuneCountInString(%s) > %d", expr, *s.MaxLength), "must be at most %d characters", *s.MaxLength)
+		}
+		if len(s.Enum) > 0 {
+			var conds, values []string
+			for _, v := range s.Enum {
+				value := fmt.Sprint(v)
+				conds = append(conds, fmt.Sprintf("%s == %s", expr, strconv.Quote(value)))
+				values = append(values, value)
+			}
+			add(fmt.Sprintf("%s!(%s)", guard, strings.Join(conds, " || ")), "must be one of: %s", strings.Join(values, ", "))
+		}
+	case "integer", "number":
+		// Optional numbers are only checked when set (non-zero)
+		guard := expr + " != 0 && "
+		if required {
+			guard = ""
+		}
+		if s.Minimum != nil {
+			minimum := *s.Minimum
+			if s.Type == "integer" {
+				minimum = math.Ceil(minimum)
+			}
+			add(fmt.Sprintf("%s%s < %s", guard, expr, formatNumber(minimum)), "must be at least %s", formatNumber(minimum))
+		}
+		if s.Maximum != nil {
+			maximum := *s.Maximum
+			if s.Type == "integer" {
+				maximum = math.Floor(maximum)
+			}
+			add(fmt.Sprintf("%s%s > %s", guard, expr, formatNumber(maximum)), "must be at most %s", formatNumber(maximum))
+		}
+	case "array":
+		// Optional arrays are only checked when set
+		guard := expr + " != nil && "
+		if required {
+			guard = ""
+		}
+		if s.MinItems != nil && *s.MinItems > 0 {
+			add(fmt.Sprintf("%slen(%s) < %d", guard, expr, *s.MinItems), "must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil {
+			add(fmt.Sprintf("len(%s) > %d", expr, *s.MaxItems), "must have at most %d items", *s.MaxItems)
+		}
+	}
+	return checks
+}
+
+// nestedMode reports how a field's nested structs are validated
+func (c *converter) nestedMode(s *schema, required bool) string {
+	if s == nil {
+		return ""
+	}
+	resolved := c.resolve(s)
+	switch {
+	case c.isStruct(s) && required:
+		return "value"
+	case c.isStruct(s):
+		return "pointer"
+	case resolved != nil && resolved.Type == "array" && c.isStruct(resolved.Items):
+		return "slice"
+	}
+	return ""
+}
+
+// isStruct reports whether a schema is generated as a struct type
+func (c *converter) isStruct(s *schema) bool {
+	if s == nil {
+		return false
+	}
+	if s.Ref != "" {
+		resolved, err := c.lookupSchema(s.Ref)
+		return err == nil && c.isObject(resolved)
+	}
+	if len(s.AllOf) == 1 && s.Properties.Len() == 0 {
+		return c.isStruct(s.AllOf[0])
+	}
+	return c.isObject(s)
+}
+
+// isObject reports whether a schema describes an object with known properties
+func (c *converter) isObject(s *schema) bool {
+	if s.Type != "" && s.Type != "object" {
+		return false
+	}
+	return s.Properties.Len() > 0 || len(s.AllOf) > 1
+}
+
+// resolve follows a schema $ref to its component, returning s unchanged otherwise
+func (c *converter) resolve(s *schema) *schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	resolved, err := c.lookupSchema(s.Ref)
+	if err != nil {
+		return nil
+	}
+	return c.resolve(resolved)
+}
+
+func (c *converter) lookupSchema(ref string) (*schema, error) {
+	if !strings.HasPrefix(ref, "#/components/schemas/") {
+		return nil, fmt.Errorf("unsupported $ref %q (only local #/components/schemas references are supported)", ref)
+	}
+	s, ok := c.doc.Components.Schemas.Get(refName(ref))
+	if !ok || s == nil {
+		return nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+	return s, nil
+}
+
+func (c *converter) resolveParameter(p *parameter) (*parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := c.doc.Components.Parameters.Get(refName(p.Ref))
+	if !strings.HasPrefix(p.Ref, "#/components/parameters/") || !ok || resolved == nil {
+		return nil, fmt.Errorf("unresolved $ref %q", p.Ref)
+	}
+	return resolved, nil
+}
+
+func (c *converter) resolveRequestBody(b *requestBody) (*requestBody, error) {
+	if b.Ref == "" {
+		return b, nil
+	}
+	resolved, ok := c.doc.Components.RequestBodies.Get(refName(b.Ref))
+	if !strings.HasPrefix(b.Ref, "#/components/requestBodies/") || !ok || resolved == nil {
+		return nil, fmt.Errorf("unresolved $ref %q", b.Ref)
+	}
+	return resolved, nil
+}
+
+func (c *converter) resolveResponse(r *response) (*response, error) {
+	if r == nil {
+		return &response{}, nil
+	}
+	if r.Ref == "" {
+		return r, nil
+	}
+	resolved, ok := c.doc.Components.Responses.Get(refName(r.Ref))
+	if !strings.HasPrefix(r.Ref, "#/components/responses/") || !ok || resolved == nil {
+		return nil, fmt.Errorf("unresolved $ref %q", r.Ref)
+	}
+	return resolved, nil
+}
+
+// reserveType claims a generated type name, failing on collisions
+func (c *converter) reserveType(name string) error {
+	if c.types[name] {
+		if slices.Contains(templateIdentifiers, name) {
+			return fmt.Errorf("type name %s collides with an identifier the generated server declares", name)
+		}
+		return fmt.Errorf("duplicate type name %s", name)
+	}
+	c.types[name] = true
+	return nil
+}
+
+// setImportFlags records which packages the generated files need
+func (c *converter) setImportFlags() {
+	for _, t := range c.spec.Types {
+		if strings.Contains(t.Alias, "time.Time") {
+			c.spec.NeedsTime = true
+		}
+		for _, f := range t.Fields {
+			if len(f.Checks) > 0 {
+				c.spec.NeedsErrors = true
+			}
+			for _, check := range f.Checks {
+				if strings.Contains(check.Cond, "utf8.") {
+					c.spec.NeedsUTF8 = true
+				}
+			}
+			if f.Nested != "" {
+				c.spec.NeedsFmt = true
+			}
+			if strings.Contains(f.Type, "time.Time") {
+				c.spec.NeedsTime = true
+			}
+		}
+	}
+	for _, t := range c.spec.Tags {
+		for _, o := range t.Operations {
+			if o.HasBody && !o.BodyRequired {
+				c.spec.NeedsIO = true
+			}
+			for _, p := range o.Params {
+				if p.Parse != "" {
+					c.spec.NeedsStrconv = true
+				}
+			}
+		}
+	}
+}
+
+// alignFields pads struct fields so the generated structs are aligned like gofmt output
+func (c *converter) alignFields() {
+	for _, t := range c.spec.Types {
+		nameWidth, typeWidth := 0, 0
+		for _, f := range t.Fields {
+			nameWidth = max(nameWidth, len(f.Name))
+			typeWidth = max(typeWidth, len(f.Type))
+		}
+		for _, f := range t.Fields {
+			f.NamePad = strings.Repeat(" ", nameWidth-len(f.Name))
+			f.TypePad = strings.Repeat(" ", typeWidth-len(f.Type))
+		}
+	}
+}
+
+// jsonSchema returns the schema of the JSON media type in a content map
+func jsonSchema(content orderedMap[*mediaType]) *schema {
+	for _, mediaType := range content.Keys() {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			m, _ := content.Get(mediaType)
+			if m != nil {
+				return m.Schema
+			}
+		}
+	}
+	return nil
+}
+
+// refName returns the last path segment of a $ref
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,300 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const petsSpec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer, format: int32, minimum: 1, maximum: 100}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: createPet
+      tags: [pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+  /pets/{pet_id}:
+    delete:
+      tags: [admin]
+      parameters:
+        - {name: pet_id, in: path, required: true, schema: {type: string}}
+      responses:
+        "204": {description: deleted}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, maxLength: 50}
+        created_at: {type: string, format: date-time}
+        tags:
+          type: array
+          items: {type: string}
+          minItems: 1
+          maxItems: 5
+        owner:
+          type: object
+          properties:
+            email: {type: string}
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	spec, err := Parse([]byte(petsSpec))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if spec.Title != "Pets" {
+		t.Errorf("expected title Pets, got %s", spec.Title)
+	}
+	if len(spec.Tags) != 2 || spec.Tags[0].Interface != "PetsAPI" || spec.Tags[1].Interface != "AdminAPI" {
+		t.Fatalf("expected PetsAPI and AdminAPI interfaces, got %+v", spec.Tags)
+	}
+	if !spec.NeedsTime || !spec.NeedsStrconv || !spec.NeedsErrors || !spec.NeedsFmt || !spec.NeedsUTF8 {
+		t.Errorf("expected all import flags to be set, got %+v", spec)
+	}
+
+	ops := spec.Tags[0].Operations
+	if len(ops) != 2 {
+		t.Fatalf("expected 2 pets operations, got %d", len(ops))
+	}
+	list, create := ops[0], ops[1]
+	if list.Name != "ListPets" || list.Method != "Get" || list.ResponseType != "[]Pet" || list.Status != "http.StatusOK" {
+		t.Errorf("unexpected listPets operation: %+v", list)
+	}
+	if len(list.Params) != 1 || list.Params[0].Parse == "" || list.Params[0].Convert != "int32(v)" {
+		t.Errorf("expected an int32 limit parameter, got %+v", list.Params)
+	}
+	if !create.HasBody || !create.BodyRequired || create.BodyType != "Pet" || create.ResponseType != "*Pet" || create.Status != "http.StatusCreated" {
+		t.Errorf("unexpected createPet operation: %+v", create)
+	}
+
+	del := spec.Tags[1].Operations[0]
+	if del.Name != "DeletePetsPetID" || del.ResponseType != "" || del.Status != "http.StatusNoContent" {
+		t.Errorf("unexpected delete operation: %+v", del)
+	}
+	if !del.Params[0].Required || del.Params[0].Source != `chi.URLParam(r, "pet_id")` {
+		t.Errorf("expected a required path parameter, got %+v", del.Params[0])
+	}
+}
+
+func TestParseTypes(t *testing.T) {
+	t.Parallel()
+	spec, err := Parse([]byte(petsSpec))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	types := make(map[string]*Type)
+	for _, typ := range spec.Types {
+		types[typ.Name] = typ
+	}
+	for _, name := range []string{"Pet", "PetOwner", "ListPetsParams", "CreatePetParams", "DeletePetsPetIDParams"} {
+		if types[name] == nil {
+			t.Errorf("expected type %s to be generated", name)
+		}
+	}
+
+	fields := make(map[string]*Field)
+	for _, f := range types["Pet"].Fields {
+		fields[f.Name] = f
+	}
+	tests := []struct {
+		field  string
+		goType string
+		json   string
+		checks int
+		nested string
+	}{
+		{"Name", "string", "name", 2, ""},
+		{"CreatedAt", "time.Time", "created_at,omitempty", 0, ""},
+		{"Tags", "[]string", "tags,omitempty", 2, ""},
+		{"Owner", "*PetOwner", "owner,omitempty", 0, "pointer"},
+	}
+	for _, tt := range tests {
+		f := fields[tt.field]
+		if f == nil {
+			t.Errorf("expected field %s on Pet", tt.field)
+			continue
+		}
+		if f.Type != tt.goType || f.JSON != tt.json || len(f.Checks) != tt.checks || f.Nested != tt.nested {
+			t.Errorf("unexpected field %s: %+v", tt.field, f)
+		}
+	}
+
+	// Lengths count characters, so multi-byte names are not rejected early
+	if cond := fields["Name"].Checks[1].Cond; cond != "utf8.RuneCountInString(v.Name) > 50" {
+		t.Errorf("expected name length to count runes, got %q", cond)
+	}
+
+	limit := types["ListPetsParams"].Fields[0]
+	if len(limit.Checks) != 2 || !strings.HasPrefix(limit.Checks[0].Cond, "v.Limit != 0 && ") {
+		t.Errorf("expected optional limit checks to be guarded, got %+v", limit.Checks)
+	}
+
+	// Leaving out an optional array does not fail its minItems
+	if cond := fields["Tags"].Checks[0].Cond; cond != "v.Tags != nil && len(v.Tags) < 1" {
+		t.Errorf("expected optional tags minItems check to be guarded, got %q", cond)
+	}
+}
+
+func TestParseTemplateIdentifiers(t *testing.T) {
+	t.Parallel()
+	spec, err := Parse([]byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Error'}
+components:
+  schemas:
+    Error:
+      type: object
+      required: [code, message]
+      properties:
+        code: {type: integer, format: int32}
+        message: {type: string}
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// The server template declares Error, so the schema is renamed
+	var names []string
+	for _, typ := range spec.Types {
+		names = append(names, typ.Name)
+		if typ.Name == "Error" {
+			t.Errorf("expected the Error schema to be renamed, got type Error")
+		}
+	}
+	if !slices.Contains(names, "ErrorSchema") {
+		t.Errorf("expected type ErrorSchema, got %v", names)
+	}
+	if op := spec.Tags[0].Operations[0]; op.ResponseType != "*ErrorSchema" {
+		t.Errorf("expected $refs to Error to use ErrorSchema, got %s", op.ResponseType)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{
+			name:    "invalid YAML",
+			spec:    "openapi: [",
+			wantErr: "failed to parse OpenAPI document",
+		},
+		{
+			name:    "Swagger 2",
+			spec:    "swagger: '2.0'\npaths: {}",
+			wantErr: "unsupported OpenAPI version",
+		},
+		{
+			name:    "no operations",
+			spec:    "openapi: 3.0.0\npaths: {}",
+			wantErr: "no operations",
+		},
+		{
+			name: "remote ref",
+			spec: `
+openapi: 3.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: 'https://example.com/pet.yaml'}
+`,
+			wantErr: "unsupported $ref",
+		},
+		{
+			name: "cookie parameter",
+			spec: `
+openapi: 3.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: session, in: cookie, schema: {type: string}}
+      responses:
+        "200": {description: ok}
+`,
+			wantErr: "unsupported location",
+		},
+		{
+			name: "duplicate operation",
+			spec: `
+openapi: 3.0.0
+paths:
+  /pets:
+    get:
+      operationId: pets
+      responses:
+        "200": {description: ok}
+  /animals:
+    get:
+      operationId: pets
+      responses:
+        "200": {description: ok}
+`,
+			wantErr: "duplicate operation name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Parse([]byte(tt.spec))
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -2,12 +2,20 @@ package generator
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/anmho/create-go-service/internal/generator/api"
 	"github.com/anmho/create-go-service/internal/generator/config"
 	"github.com/anmho/create-go-service/internal/generator/database"
 	"github.com/anmho/create-go-service/internal/generator/deployment"
+	"github.com/anmho/create-go-service/internal/generator/events"
+	"github.com/anmho/create-go-service/internal/generator/jobs"
+	"github.com/anmho/create-go-service/internal/generator/openapi"
+	"github.com/anmho/create-go-service/internal/generator/proto"
+	"github.com/anmho/create-go-service/internal/generator/ratelimit"
 )
 
 // fileMapping represents a source template to output file mapping
@@ -28,6 +36,10 @@ type Generator struct {
 	config         ProjectConfig
 	fs             FileSystem
 	templateLoader TemplateLoader
+	openAPI        *openapi.Spec         // Parsed OpenAPI spec, set when API.OpenAPISpec is configured
+	protos         *proto.Set            // Services discovered in API.ProtoDir
+	postKeys       []database.KeyBuilder // Post key builders, set for the DynamoDB single-table design
+	protoSources   map[string][]byte
 }
 
 // NewGenerator creates a new generator with default dependencies
@@ -49,6 +61,17 @@ func NewGeneratorWithDeps(config ProjectConfig, fs FileSystem, loader TemplateLo
 }
 
 func (g *Generator) Generate() error {
+	// Parse the OpenAPI spec and proto tree before writing anything so invalid input fails fast
+	if err := g.loadOpenAPISpec(); err != nil {
+		return err
+	}
+	if err := g.loadProtoTree(); err != nil {
+		return err
+	}
+	if err := g.loadKeySchemas(); err != nil {
+		return err
+	}
+
 	// Create output directory
 	if err := g.fs.MkdirAll(g.config.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -72,6 +95,11 @@ func (g *Generator) Generate() error {
 		}
 	}
 
+	// Copy the existing proto tree into the project's buf module
+	if err := g.writeProtoFiles(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -133,6 +161,24 @@ func (g *Generator) getFileGenerationRules() []fileGenerationRule {
 		},
 	})
 
+	// Request context files (always generated)
+	rules = append(rules, fileGenerationRule{
+		files: []fileMapping{
+			{"internal/reqctx/reqctx.go", "reqctx/reqctx.go.tmpl"},
+			{"internal/reqctx/log.go", "reqctx/log.go.tmpl"},
+			{"internal/reqctx/reqctx_test.go", "reqctx/reqctx_test.go.tmpl"},
+		},
+	})
+
+	// Logging files (always generated)
+	rules = append(rules, fileGenerationRule{
+		files: []fileMapping{
+			{"internal/logging/logging.go", "logging/logging.go.tmpl"},
+			{"internal/logging/redact.go", "logging/redact.go.tmpl"},
+			{"internal/logging/logging_test.go", "logging/logging_test.go.tmpl"},
+		},
+	})
+
 	// API type-specific files
 	for _, apiType := range g.config.API.Types {
 		switch apiType {
@@ -141,9 +187,25 @@ func (g *Generator) getFileGenerationRules() []fileGenerationRule {
 				files: []fileMapping{
 					{"internal/api/server.go", "chi/server.go.tmpl"},
 					{"internal/json/json.go", "chi/json.go.tmpl"},
-					{"internal/posts/handlers.go", "posts/handlers.go.tmpl"},
 				},
 			})
+			// Handlers come from the OpenAPI spec when one is provided
+			if g.config.API.OpenAPISpec != "" {
+				rules = append(rules, fileGenerationRule{
+					files: []fileMapping{
+						{"internal/openapi/types.go", "openapi/types.go.tmpl"},
+						{"internal/openapi/server.go", "openapi/server.go.tmpl"},
+						{"internal/openapi/handlers.go", "openapi/handlers.go.tmpl"},
+					},
+				})
+			} else {
+				rules = append(rules, fileGenerationRule{
+					files: []fileMapping{
+						{"internal/posts/handlers.go", "posts/handlers.go.tmpl"},
+						{"internal/posts/handlers_test.go", "posts/handlers_test.go.tmpl"},
+					},
+				})
+			}
 			// Generate main.go only if not gRPC
 			if !g.hasAPIType(api.TypeGRPC) {
 				rules = append(rules, fileGenerationRule{
@@ -163,16 +225,36 @@ func (g *Generator) getFileGenerationRules() []fileGenerationRule {
 					{"cmd/api/main.go", "grpc/main.go.tmpl"},
 				},
 			})
+			// Handler stubs for the services of an existing proto tree
+			if g.config.API.ProtoDir != "" {
+				rules = append(rules, fileGenerationRule{
+					files: []fileMapping{
+						{"internal/api/service_handlers.go", "grpc/service_handlers.go.tmpl"},
+					},
+				})
+			}
 		}
 	}
 
 	// Database type-specific files
 	switch g.config.Database.Type {
 	case database.TypeDynamoDB:
+		tableTemplate := "posts/dynamodb_table.go.tmpl"
+		if g.config.Database.Design == database.DesignSingleTable {
+			tableTemplate = "posts/dynamodb_single_table.go.tmpl"
+			// Shared table schema plus the post key builders generated from its key schema
+			rules = append(rules, fileGenerationRule{
+				files: []fileMapping{
+					{"internal/database/dynamodb_table.go", "dynamodb/dynamodb_table.go.tmpl"},
+					{"internal/posts/dynamodb_keys.go", "posts/dynamodb_keys.go.tmpl"},
+					{"internal/posts/dynamodb_keys_test.go", "posts/dynamodb_keys_test.go.tmpl"},
+				},
+			})
+		}
 		rules = append(rules, fileGenerationRule{
 			files: []fileMapping{
 				{"internal/database/dynamodb.go", "dynamodb/dynamodb.go.tmpl"},
-				{"internal/posts/dynamodb_table.go", "posts/dynamodb_table.go.tmpl"},
+				{"internal/posts/dynamodb_table.go", tableTemplate},
 				{"internal/posts/post_table_test.go", "posts/dynamodb_table_test.go.tmpl"},
 			},
 		})
@@ -186,14 +268,67 @@ func (g *Generator) getFileGenerationRules() []fileGenerationRule {
 			},
 		})
 	case database.TypePostgres:
+		tableTemplate := "posts/postgres_table.go.tmpl"
+		if g.config.Database.Codegen == database.CodegenSQLC {
+			tableTemplate = "posts/postgres_sqlc_table.go.tmpl"
+			// Generated code is included so the project builds before sqlc is installed
+			rules = append(rules, fileGenerationRule{
+				files: []fileMapping{
+					{"sqlc.yaml", "sqlc/sqlc.yaml.tmpl"},
+					{"queries/posts.sql", "sqlc/queries/posts.sql.tmpl"},
+					{"internal/posts/postsdb/db.go", "sqlc/postsdb/db.go.tmpl"},
+					{"internal/posts/postsdb/models.go", "sqlc/postsdb/models.go.tmpl"},
+					{"internal/posts/postsdb/posts.sql.go", "sqlc/postsdb/posts.sql.go.tmpl"},
+					{".github/workflows/sqlc.yml", "github/workflows/sqlc.yml.tmpl"},
+				},
+			})
+		}
 		rules = append(rules, fileGenerationRule{
 			files: []fileMapping{
 				{"internal/database/postgres.go", "postgres/postgres.go.tmpl"},
-				{"internal/posts/postgres_table.go", "posts/postgres_table.go.tmpl"},
+				{"internal/posts/postgres_table.go", tableTemplate},
 				{"internal/posts/post_table_test.go", "posts/postgres_table_test.go.tmpl"},
 				{"atlas.hcl", "atlas/atlas.hcl.tmpl"},
 				{"migrations/001_initial.up.sql", "atlas/migrations/001_initial.up.sql.tmpl"},
 				{"migrations/001_initial.down.sql", "atlas/migrations/001_initial.down.sql.tmpl"},
+				{"migrations/002_posts_keyset_index.up.sql", "atlas/migrations/002_posts_keyset_index.up.sql.tmpl"},
+				{"migrations/002_posts_keyset_index.down.sql", "atlas/migrations/002_posts_keyset_index.down.sql.tmpl"},
+				{"migrations/003_posts_version.up.sql", "atlas/migrations/003_posts_version.up.sql.tmpl"},
+				{"migrations/003_posts_version.down.sql", "atlas/migrations/003_posts_version.down.sql.tmpl"},
+			},
+		})
+	case database.TypeMySQL:
+		rules = append(rules, fileGenerationRule{
+			files: []fileMapping{
+				{"internal/database/mysql.go", "mysql/mysql.go.tmpl"},
+				{"internal/posts/mysql_table.go", "posts/mysql_table.go.tmpl"},
+				{"internal/posts/post_table_test.go", "posts/mysql_table_test.go.tmpl"},
+				{"atlas.hcl", "atlas/atlas.hcl.tmpl"},
+				{"migrations/001_initial.up.sql", "mysql/migrations/001_initial.up.sql.tmpl"},
+				{"migrations/001_initial.down.sql", "mysql/migrations/001_initial.down.sql.tmpl"},
+				{"migrations/002_posts_version.up.sql", "mysql/migrations/002_posts_version.up.sql.tmpl"},
+				{"migrations/002_posts_version.down.sql", "mysql/migrations/002_posts_version.down.sql.tmpl"},
+			},
+		})
+	case database.TypeMongoDB:
+		// Indexes are created by NewPostTable at startup
+		rules = append(rules, fileGenerationRule{
+			files: []fileMapping{
+				{"internal/database/mongodb.go", "mongodb/mongodb.go.tmpl"},
+				{"internal/posts/mongodb_table.go", "posts/mongodb_table.go.tmpl"},
+				{"internal/posts/post_table_test.go", "posts/mongodb_table_test.go.tmpl"},
+			},
+		})
+	case database.TypeSQLite:
+		// Migrations are embedded into the binary and applied at startup
+		rules = append(rules, fileGenerationRule{
+			files: []fileMapping{
+				{"internal/database/sqlite.go", "sqlite/sqlite.go.tmpl"},
+				{"internal/database/migrations/001_initial.sql", "sqlite/migrations/001_initial.sql.tmpl"},
+				{"internal/database/migrations/002_posts_keyset_index.sql", "sqlite/migrations/002_posts_keyset_index.sql.tmpl"},
+				{"internal/database/migrations/003_posts_version.sql", "sqlite/migrations/003_posts_version.sql.tmpl"},
+				{"internal/posts/sqlite_table.go", "posts/sqlite_table.go.tmpl"},
+				{"internal/posts/post_table_test.go", "posts/sqlite_table_test.go.tmpl"},
 			},
 		})
 	}
@@ -203,29 +338,81 @@ func (g *Generator) getFileGenerationRules() []fileGenerationRule {
 		files: []fileMapping{
 			{"internal/posts/post.go", "posts/post.go.tmpl"},
 			{"internal/posts/service.go", "posts/service.go.tmpl"},
+			{"internal/posts/instrumented_table.go", "posts/instrumented_table.go.tmpl"},
+			{"internal/posts/instrumented_table_test.go", "posts/instrumented_table_test.go.tmpl"},
+		},
+	})
+
+	// Keyset page cursors (DynamoDB encodes its LastEvaluatedKey instead)
+	rules = append(rules, fileGenerationRule{
+		files: []fileMapping{
+			{"internal/posts/cursor.go", "posts/cursor.go.tmpl"},
+			{"internal/posts/cursor_test.go", "posts/cursor_test.go.tmpl"},
+		},
+		condition: func(g *Generator) bool {
+			return g.config.Database.Type != database.TypeDynamoDB
+		},
+	})
+
+	// Converters between Post and the proto and DynamoDB storage representations
+	rules = append(rules, fileGenerationRule{
+		files: []fileMapping{
 			{"internal/posts/converters.go", "posts/converters.go.tmpl"},
 			{"internal/posts/converters_test.go", "posts/converters_test.go.tmpl"},
 		},
+		condition: func(g *Generator) bool {
+			return g.hasAPIType(api.TypeGRPC) || g.config.Database.Type == database.TypeDynamoDB
+		},
 	})
 
 	// Feature-specific files
 	for _, feature := range g.config.Features {
 		switch feature {
 		case config.FeatureAuth:
+			rules = append(rules, g.authRules()...)
+		case config.FeaturePostHog:
 			rules = append(rules, fileGenerationRule{
 				files: []fileMapping{
-					{"internal/auth/jwt.go", "auth/jwt.go.tmpl"},
+					{"internal/posthog/posthog.go", "posthog/posthog.go.tmpl"},
 				},
 			})
-		case config.FeaturePostHog:
+		case config.FeatureSoftDelete:
+			rules = append(rules, g.softDeleteRules()...)
+		case config.FeatureOutbox:
+			rules = append(rules, g.outboxRules()...)
+		case config.FeatureJobs:
+			rules = append(rules, g.jobsRules()...)
+		case config.FeatureScheduler:
+			rules = append(rules, g.schedulerRules()...)
+		case config.FeatureAPIKeys:
+			rules = append(rules, g.apiKeysRules()...)
+		case config.FeatureTokens:
+			rules = append(rules, g.tokensRules()...)
+		case config.FeatureTracing:
 			rules = append(rules, fileGenerationRule{
 				files: []fileMapping{
-					{"internal/posthog/posthog.go", "posthog/posthog.go.tmpl"},
+					{"internal/tracing/tracing.go", "tracing/tracing.go.tmpl"},
+					{"internal/tracing/tracing_test.go", "tracing/tracing_test.go.tmpl"},
+				},
+			})
+		case config.FeatureObservability:
+			rules = append(rules, fileGenerationRule{
+				files: []fileMapping{
+					{"observability/prometheus/prometheus.yml", "observability/prometheus.yml.tmpl"},
+					{"observability/prometheus/alerts.yml", "observability/alerts.yml.tmpl"},
+					{"observability/grafana/provisioning/datasources/prometheus.yml", "observability/grafana-datasources.yml.tmpl"},
+					{"observability/grafana/provisioning/dashboards/dashboards.yml", "observability/grafana-dashboards.yml.tmpl"},
+					{"observability/grafana/dashboards/service.json", "observability/dashboard.json.tmpl"},
 				},
 			})
 		}
 	}
 
+	// API keys and refresh tokens share their format and their store test fixtures
+	if slices.Contains(g.config.Features, config.FeatureAPIKeys) || slices.Contains(g.config.Features, config.FeatureTokens) {
+		rules = append(rules, g.credentialRules()...)
+	}
+
 	// Deployment type-specific files
 	switch g.config.Deployment.Type {
 	case deployment.TypeFly:
@@ -245,6 +432,465 @@ func (g *Generator) getFileGenerationRules() []fileGenerationRule {
 	return rules
 }
 
+// loadOpenAPISpec reads and parses the configured OpenAPI spec, if any
+func (g *Generator) loadOpenAPISpec() error {
+	if g.config.API.OpenAPISpec == "" {
+		return nil
+	}
+	if !g.hasAPIType(api.TypeChi) {
+		return fmt.Errorf("OpenAPI-first generation requires the chi API type")
+	}
+
+	data, err := g.fs.ReadFile(g.config.API.OpenAPISpec)
+	if err != nil {
+		return fmt.Errorf("failed to read OpenAPI spec: %w", err)
+	}
+	spec, err := openapi.Parse(data)
+	if err != nil {
+		return fmt.Errorf("invalid OpenAPI spec %s: %w", g.config.API.OpenAPISpec, err)
+	}
+	g.openAPI = spec
+	return nil
+}
+
+// loadKeySchemas expands the entity key schemas of the DynamoDB single-table design into key builders
+func (g *Generator) loadKeySchemas() error {
+	if g.config.Database.Type != database.TypeDynamoDB || g.config.Database.Design != database.DesignSingleTable {
+		return nil
+	}
+
+	builders, err := database.PostKeySchema.Builders()
+	if err != nil {
+		return fmt.Errorf("invalid key schema: %w", err)
+	}
+	g.postKeys = builders
+	return nil
+}
+
+// loadProtoTree discovers the services in the configured proto tree, if any
+func (g *Generator) loadProtoTree() error {
+	if g.config.API.ProtoDir == "" {
+		return nil
+	}
+	if !g.hasAPIType(api.TypeGRPC) {
+		return fmt.Errorf("proto-first generation requires the grpc API type")
+	}
+
+	g.protoSources = make(map[string][]byte)
+	var files []*proto.File
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := g.fs.ReadDir(filepath.Join(g.config.API.ProtoDir, dir))
+		if err != nil {
+			return fmt.Errorf("failed to read proto directory: %w", err)
+		}
+		for _, entry := range entries {
+			rel := path.Join(dir, entry.Name())
+			// Generated code and vendored googleapis (provided by the buf dependency) are skipped
+			if entry.IsDir() {
+				if entry.Name() == "gen" || rel == "google" || strings.HasPrefix(entry.Name(), ".") {
+					continue
+				}
+				if err := walk(rel); err != nil {
+					return err
+				}
+				continue
+			}
+			if filepath.Ext(rel) != ".proto" {
+				continue
+			}
+			if rel == "posts/v1/posts.proto" {
+				return fmt.Errorf("%s conflicts with the generated posts service", rel)
+			}
+
+			data, err := g.fs.ReadFile(filepath.Join(g.config.API.ProtoDir, rel))
+			if err != nil {
+				return fmt.Errorf("failed to read proto file: %w", err)
+			}
+			file, err := proto.Parse(rel, data)
+			if err != nil {
+				return err
+			}
+			if file.Package == "posts.v1" {
+				return fmt.Errorf("%s: package posts.v1 conflicts with the generated posts service", rel)
+			}
+			g.protoSources[rel] = data
+			files = append(files, file)
+		}
+		return nil
+	}
+	if err := walk(""); err != nil {
+		return err
+	}
+
+	set, err := proto.Resolve(files, g.config.ModulePath+"/protos/gen")
+	if err != nil {
+		return fmt.Errorf("invalid proto tree %s: %w", g.config.API.ProtoDir, err)
+	}
+	if len(set.Services) == 0 {
+		return fmt.Errorf("no services found in %s", g.config.API.ProtoDir)
+	}
+	g.protos = set
+	return nil
+}
+
+// writeProtoFiles copies the discovered proto tree into the project's protos directory
+func (g *Generator) writeProtoFiles() error {
+	for rel, data := range g.protoSources {
+		fullPath := filepath.Join(g.config.OutputDir, "protos", filepath.FromSlash(rel))
+		if err := g.fs.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := g.fs.WriteFile(fullPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// softDeleteRules returns the deleted_at migration for the configured database and the purge job
+// DynamoDB needs neither: the column is schemaless and expired posts are removed by TTL
+func (g *Generator) softDeleteRules() []fileGenerationRule {
+	var files []fileMapping
+	switch g.config.Database.Type {
+	case database.TypePostgres:
+		files = []fileMapping{
+			{"migrations/004_posts_soft_delete.up.sql", "atlas/migrations/004_posts_soft_delete.up.sql.tmpl"},
+			{"migrations/004_posts_soft_delete.down.sql", "atlas/migrations/004_posts_soft_delete.down.sql.tmpl"},
+		}
+	case database.TypeMySQL:
+		files = []fileMapping{
+			{"migrations/003_posts_soft_delete.up.sql", "mysql/migrations/003_posts_soft_delete.up.sql.tmpl"},
+			{"migrations/003_posts_soft_delete.down.sql", "mysql/migrations/003_posts_soft_delete.down.sql.tmpl"},
+		}
+	case database.TypeSQLite:
+		files = []fileMapping{
+			{"internal/database/migrations/004_posts_soft_delete.sql", "sqlite/migrations/004_posts_soft_delete.sql.tmpl"},
+		}
+	case database.TypeDynamoDB:
+		return nil
+	}
+
+	files = append(files,
+		fileMapping{"internal/posts/purge.go", "posts/purge.go.tmpl"},
+		fileMapping{"internal/posts/purge_test.go", "posts/purge_test.go.tmpl"},
+	)
+	return []fileGenerationRule{{files: files}}
+}
+
+// outboxRules returns the outbox package, the post events, the outbox table for the configured database
+// and the publisher for the configured message broker
+// DynamoDB and MongoDB need no migration: the outbox is created at startup along with the posts table or collection
+func (g *Generator) outboxRules() []fileGenerationRule {
+	files := []fileMapping{
+		{"internal/outbox/outbox.go", "outbox/outbox.go.tmpl"},
+		{"internal/outbox/relay.go", "outbox/relay.go.tmpl"},
+		{"internal/outbox/relay_test.go", "outbox/relay_test.go.tmpl"},
+		{"internal/outbox/publisher.go", "outbox/publisher.go.tmpl"},
+		{"internal/posts/events.go", "posts/events.go.tmpl"},
+	}
+	switch g.config.Database.Type {
+	case database.TypePostgres:
+		files = append(files,
+			fileMapping{"migrations/005_outbox.up.sql", "atlas/migrations/005_outbox.up.sql.tmpl"},
+			fileMapping{"migrations/005_outbox.down.sql", "atlas/migrations/005_outbox.down.sql.tmpl"},
+		)
+		if g.config.Database.Codegen == database.CodegenSQLC {
+			files = append(files,
+				fileMapping{"queries/outbox.sql", "sqlc/queries/outbox.sql.tmpl"},
+				fileMapping{"internal/posts/postsdb/outbox.sql.go", "sqlc/postsdb/outbox.sql.go.tmpl"},
+			)
+		}
+	case database.TypeMySQL:
+		files = append(files,
+			fileMapping{"migrations/004_outbox.up.sql", "mysql/migrations/004_outbox.up.sql.tmpl"},
+			fileMapping{"migrations/004_outbox.down.sql", "mysql/migrations/004_outbox.down.sql.tmpl"},
+		)
+	case database.TypeSQLite:
+		files = append(files,
+			fileMapping{"internal/database/migrations/005_outbox.sql", "sqlite/migrations/005_outbox.sql.tmpl"},
+		)
+	case database.TypeDynamoDB:
+		files = append(files,
+			fileMapping{"internal/posts/dynamodb_outbox.go", "posts/dynamodb_outbox.go.tmpl"},
+			fileMapping{"internal/posts/dynamodb_outbox_test.go", "posts/dynamodb_outbox_test.go.tmpl"},
+		)
+	}
+	switch g.config.Events.Type {
+	case events.TypeNATS:
+		files = append(files,
+			fileMapping{"internal/outbox/nats.go", "outbox/nats.go.tmpl"},
+			fileMapping{"internal/outbox/nats_test.go", "outbox/nats_test.go.tmpl"},
+		)
+	case events.TypeKafka:
+		files = append(files,
+			fileMapping{"internal/outbox/kafka.go", "outbox/kafka.go.tmpl"},
+			fileMapping{"internal/outbox/kafka_test.go", "outbox/kafka_test.go.tmpl"},
+		)
+	case events.TypeSQS:
+		files = append(files,
+			fileMapping{"internal/outbox/sqs.go", "outbox/sqs.go.tmpl"},
+			fileMapping{"internal/outbox/sqs_test.go", "outbox/sqs_test.go.tmpl"},
+		)
+	case events.TypePubSub:
+		files = append(files,
+			fileMapping{"internal/outbox/pubsub.go", "outbox/pubsub.go.tmpl"},
+			fileMapping{"internal/outbox/pubsub_test.go", "outbox/pubsub_test.go.tmpl"},
+		)
+	}
+	return []fileGenerationRule{{files: files}}
+}
+
+// jobsRules returns the jobs package with the queue for the configured job store and the jobs CLI command
+// The Postgres queue needs the jobs table migration; Redis needs no schema
+func (g *Generator) jobsRules() []fileGenerationRule {
+	files := []fileMapping{
+		{"internal/jobs/jobs.go", "jobs/jobs.go.tmpl"},
+		{"internal/jobs/worker.go", "jobs/worker.go.tmpl"},
+		{"internal/jobs/worker_test.go", "jobs/worker_test.go.tmpl"},
+		{"internal/jobs/memory.go", "jobs/memory.go.tmpl"},
+		{"internal/jobs/queue_test.go", "jobs/queue_test.go.tmpl"},
+		{"internal/cli/jobs.go", "cli/jobs.go.tmpl"},
+	}
+	switch g.jobQueue() {
+	case jobs.TypePostgres:
+		files = append(files,
+			fileMapping{"internal/jobs/postgres.go", "jobs/postgres.go.tmpl"},
+			fileMapping{"internal/jobs/postgres_test.go", "jobs/postgres_test.go.tmpl"},
+			fileMapping{"migrations/006_jobs.up.sql", "atlas/migrations/006_jobs.up.sql.tmpl"},
+			fileMapping{"migrations/006_jobs.down.sql", "atlas/migrations/006_jobs.down.sql.tmpl"},
+		)
+	case jobs.TypeRedis:
+		files = append(files,
+			fileMapping{"internal/jobs/redis.go", "jobs/redis.go.tmpl"},
+			fileMapping{"internal/jobs/redis_test.go", "jobs/redis_test.go.tmpl"},
+		)
+	}
+	return []fileGenerationRule{{files: files}}
+}
+
+// jobQueue returns the store jobs are queued in, or TypeNone without the jobs feature
+// Unless one is configured, jobs are queued in Postgres when it is the database and in Redis otherwise
+func (g *Generator) jobQueue() jobs.Type {
+	if !slices.Contains(g.config.Features, config.FeatureJobs) {
+		return jobs.TypeNone
+	}
+	if g.config.Jobs.Type != jobs.TypeNone {
+		return g.config.Jobs.Type
+	}
+	if g.config.Database.Type == database.TypePostgres {
+		return jobs.TypePostgres
+	}
+	return jobs.TypeRedis
+}
+
+// schedulerRules returns the scheduler package with the leader elector for the configured database
+// SQLite runs on a single machine, so it needs no elector of its own and uses LocalElector
+func (g *Generator) schedulerRules() []fileGenerationRule {
+	files := []fileMapping{
+		{"internal/scheduler/scheduler.go", "scheduler/scheduler.go.tmpl"},
+		{"internal/scheduler/scheduler_test.go", "scheduler/scheduler_test.go.tmpl"},
+		{"internal/scheduler/elector.go", "scheduler/elector.go.tmpl"},
+	}
+	switch g.config.Database.Type {
+	case database.TypePostgres:
+		files = append(files,
+			fileMapping{"internal/scheduler/postgres.go", "scheduler/postgres.go.tmpl"},
+			fileMapping{"internal/scheduler/postgres_test.go", "scheduler/postgres_test.go.tmpl"},
+		)
+	case database.TypeMySQL:
+		files = append(files,
+			fileMapping{"internal/scheduler/mysql.go", "scheduler/mysql.go.tmpl"},
+			fileMapping{"internal/scheduler/mysql_test.go", "scheduler/mysql_test.go.tmpl"},
+		)
+	case database.TypeMongoDB:
+		files = append(files,
+			fileMapping{"internal/scheduler/mongodb.go", "scheduler/mongodb.go.tmpl"},
+			fileMapping{"internal/scheduler/mongodb_test.go", "scheduler/mongodb_test.go.tmpl"},
+		)
+	case database.TypeDynamoDB:
+		files = append(files,
+			fileMapping{"internal/scheduler/dynamodb.go", "scheduler/dynamodb.go.tmpl"},
+			fileMapping{"internal/scheduler/dynamodb_test.go", "scheduler/dynamodb_test.go.tmpl"},
+		)
+	}
+	if g.config.Database.Type != database.TypeSQLite {
+		files = append(files, fileMapping{"internal/scheduler/elector_test.go", "scheduler/elector_test.go.tmpl"})
+	}
+	return []fileGenerationRule{{files: files}}
+}
+
+// apiKeysRules returns the apikeys package with the key store for the configured database, the middleware or
+// interceptor for the configured API types, the Redis rate limiter if configured and the keys CLI command
+// DynamoDB and MongoDB need no migration: the keys table or collection is created on first use
+func (g *Generator) apiKeysRules() []fileGenerationRule {
+	files := []fileMapping{
+		{"internal/apikeys/apikeys.go", "apikeys/apikeys.go.tmpl"},
+		{"internal/apikeys/apikeys_test.go", "apikeys/apikeys_test.go.tmpl"},
+		{"internal/apikeys/limiter.go", "apikeys/limiter.go.tmpl"},
+		{"internal/apikeys/limiter_test.go", "apikeys/limiter_test.go.tmpl"},
+		{"internal/apikeys/memory.go", "apikeys/memory.go.tmpl"},
+		{"internal/apikeys/store_test.go", "apikeys/store_test.go.tmpl"},
+		{"internal/cli/keys.go", "cli/keys.go.tmpl"},
+	}
+	switch g.config.Database.Type {
+	case database.TypePostgres:
+		files = append(files,
+			fileMapping{"internal/apikeys/postgres.go", "apikeys/postgres.go.tmpl"},
+			fileMapping{"internal/apikeys/postgres_test.go", "apikeys/postgres_test.go.tmpl"},
+			fileMapping{"migrations/007_api_keys.up.sql", "atlas/migrations/007_api_keys.up.sql.tmpl"},
+			fileMapping{"migrations/007_api_keys.down.sql", "atlas/migrations/007_api_keys.down.sql.tmpl"},
+		)
+	case database.TypeMySQL:
+		files = append(files,
+			fileMapping{"internal/apikeys/mysql.go", "apikeys/mysql.go.tmpl"},
+			fileMapping{"internal/apikeys/mysql_test.go", "apikeys/mysql_test.go.tmpl"},
+			fileMapping{"migrations/005_api_keys.up.sql", "mysql/migrations/005_api_keys.up.sql.tmpl"},
+			fileMapping{"migrations/005_api_keys.down.sql", "mysql/migrations/005_api_keys.down.sql.tmpl"},
+		)
+	case database.TypeSQLite:
+		files = append(files,
+			fileMapping{"internal/apikeys/sqlite.go", "apikeys/sqlite.go.tmpl"},
+			fileMapping{"internal/apikeys/sqlite_test.go", "apikeys/sqlite_test.go.tmpl"},
+			fileMapping{"internal/database/migrations/006_api_keys.sql", "sqlite/migrations/006_api_keys.sql.tmpl"},
+		)
+	case database.TypeMongoDB:
+		files = append(files,
+			fileMapping{"internal/apikeys/mongodb.go", "apikeys/mongodb.go.tmpl"},
+			fileMapping{"internal/apikeys/mongodb_test.go", "apikeys/mongodb_test.go.tmpl"},
+		)
+	case database.TypeDynamoDB:
+		files = append(files,
+			fileMapping{"internal/apikeys/dynamodb.go", "apikeys/dynamodb.go.tmpl"},
+			fileMapping{"internal/apikeys/dynamodb_test.go", "apikeys/dynamodb_test.go.tmpl"},
+		)
+	}
+	if g.hasAPIType(api.TypeChi) {
+		files = append(files,
+			fileMapping{"internal/apikeys/middleware.go", "apikeys/middleware.go.tmpl"},
+			fileMapping{"internal/apikeys/middleware_test.go", "apikeys/middleware_test.go.tmpl"},
+		)
+	}
+	if g.hasAPIType(api.TypeGRPC) {
+		files = append(files,
+			fileMapping{"internal/apikeys/interceptor.go", "apikeys/interceptor.go.tmpl"},
+			fileMapping{"internal/apikeys/interceptor_test.go", "apikeys/interceptor_test.go.tmpl"},
+		)
+	}
+	if g.config.RateLimit.Type == ratelimit.TypeRedis {
+		files = append(files,
+			fileMapping{"internal/apikeys/redis_limiter.go", "apikeys/redis_limiter.go.tmpl"},
+			fileMapping{"internal/apikeys/redis_limiter_test.go", "apikeys/redis_limiter_test.go.tmpl"},
+		)
+	}
+	return []fileGenerationRule{{files: files}}
+}
+
+// tokensRules returns the token issuer, its endpoints and the refresh token store for the configured database,
+// and the tokens CLI command
+// The endpoints write JSON with the json package, which only chi projects generate otherwise
+func (g *Generator) tokensRules() []fileGenerationRule {
+	files := []fileMapping{
+		{"internal/auth/handlers.go", "auth/handlers.go.tmpl"},
+		{"internal/auth/handlers_test.go", "auth/handlers_test.go.tmpl"},
+		{"internal/auth/issuer.go", "auth/issuer.go.tmpl"},
+		{"internal/auth/issuer_test.go", "auth/issuer_test.go.tmpl"},
+		{"internal/auth/refresh.go", "auth/refresh.go.tmpl"},
+		{"internal/auth/refresh_memory.go", "auth/refresh_memory.go.tmpl"},
+		{"internal/auth/refresh_store_test.go", "auth/refresh_store_test.go.tmpl"},
+		{"internal/auth/signing.go", "auth/signing.go.tmpl"},
+		{"internal/auth/signing_test.go", "auth/signing_test.go.tmpl"},
+		{"internal/cli/tokens.go", "cli/tokens.go.tmpl"},
+	}
+	switch g.config.Database.Type {
+	case database.TypePostgres:
+		files = append(files,
+			fileMapping{"internal/auth/refresh_postgres.go", "auth/refresh_postgres.go.tmpl"},
+			fileMapping{"internal/auth/refresh_postgres_test.go", "auth/refresh_postgres_test.go.tmpl"},
+			fileMapping{"migrations/008_refresh_tokens.up.sql", "atlas/migrations/008_refresh_tokens.up.sql.tmpl"},
+			fileMapping{"migrations/008_refresh_tokens.down.sql", "atlas/migrations/008_refresh_tokens.down.sql.tmpl"},
+		)
+	case database.TypeMySQL:
+		files = append(files,
+			fileMapping{"internal/auth/refresh_mysql.go", "auth/refresh_mysql.go.tmpl"},
+			fileMapping{"internal/auth/refresh_mysql_test.go", "auth/refresh_mysql_test.go.tmpl"},
+			fileMapping{"migrations/006_refresh_tokens.up.sql", "mysql/migrations/006_refresh_tokens.up.sql.tmpl"},
+			fileMapping{"migrations/006_refresh_tokens.down.sql", "mysql/migrations/006_refresh_tokens.down.sql.tmpl"},
+		)
+	case database.TypeSQLite:
+		files = append(files,
+			fileMapping{"internal/auth/refresh_sqlite.go", "auth/refresh_sqlite.go.tmpl"},
+			fileMapping{"internal/auth/refresh_sqlite_test.go", "auth/refresh_sqlite_test.go.tmpl"},
+			fileMapping{"internal/database/migrations/007_refresh_tokens.sql", "sqlite/migrations/007_refresh_tokens.sql.tmpl"},
+		)
+	case database.TypeMongoDB:
+		files = append(files,
+			fileMapping{"internal/auth/refresh_mongodb.go", "auth/refresh_mongodb.go.tmpl"},
+			fileMapping{"internal/auth/refresh_mongodb_test.go", "auth/refresh_mongodb_test.go.tmpl"},
+		)
+	case database.TypeDynamoDB:
+		files = append(files,
+			fileMapping{"internal/auth/refresh_dynamodb.go", "auth/refresh_dynamodb.go.tmpl"},
+			fileMapping{"internal/auth/refresh_dynamodb_test.go", "auth/refresh_dynamodb_test.go.tmpl"},
+		)
+	}
+	if !g.hasAPIType(api.TypeChi) {
+		files = append(files, fileMapping{"internal/json/json.go", "chi/json.go.tmpl"})
+	}
+	return []fileGenerationRule{{files: files}}
+}
+
+// credentialRules returns the credential package API keys and refresh tokens are made of, and the databasetest
+// fixture starting the configured database for their store tests
+func (g *Generator) credentialRules() []fileGenerationRule {
+	files := []fileMapping{
+		{"internal/credential/credential.go", "credential/credential.go.tmpl"},
+		{"internal/credential/credential_test.go", "credential/credential_test.go.tmpl"},
+	}
+	switch g.config.Database.Type {
+	case database.TypePostgres:
+		files = append(files, fileMapping{"internal/database/databasetest/postgres.go", "databasetest/postgres.go.tmpl"})
+	case database.TypeMySQL:
+		files = append(files, fileMapping{"internal/database/databasetest/mysql.go", "databasetest/mysql.go.tmpl"})
+	case database.TypeSQLite:
+		files = append(files, fileMapping{"internal/database/databasetest/sqlite.go", "databasetest/sqlite.go.tmpl"})
+	case database.TypeMongoDB:
+		files = append(files, fileMapping{"internal/database/databasetest/mongodb.go", "databasetest/mongodb.go.tmpl"})
+	case database.TypeDynamoDB:
+		files = append(files, fileMapping{"internal/database/databasetest/dynamodb.go", "databasetest/dynamodb.go.tmpl"})
+	}
+	return []fileGenerationRule{{files: files}}
+}
+
+// authRules returns the auth package with the chi middleware and Connect interceptor for the configured API types,
+// and the posts authorization policies
+func (g *Generator) authRules() []fileGenerationRule {
+	files := []fileMapping{
+		{"internal/auth/jwt.go", "auth/jwt.go.tmpl"},
+		{"internal/auth/jwt_test.go", "auth/jwt_test.go.tmpl"},
+		{"internal/auth/jwks.go", "auth/jwks.go.tmpl"},
+		{"internal/auth/jwks_test.go", "auth/jwks_test.go.tmpl"},
+		{"internal/auth/policy.go", "auth/policy.go.tmpl"},
+		{"internal/auth/policy_test.go", "auth/policy_test.go.tmpl"},
+		{"internal/auth/principal.go", "auth/principal.go.tmpl"},
+		{"internal/posts/policy.go", "posts/policy.go.tmpl"},
+		{"internal/posts/policy_test.go", "posts/policy_test.go.tmpl"},
+	}
+	if g.hasAPIType(api.TypeChi) {
+		files = append(files,
+			fileMapping{"internal/auth/middleware.go", "auth/middleware.go.tmpl"},
+			fileMapping{"internal/auth/middleware_test.go", "auth/middleware_test.go.tmpl"},
+		)
+	}
+	if g.hasAPIType(api.TypeGRPC) {
+		files = append(files,
+			fileMapping{"internal/auth/interceptor.go", "auth/interceptor.go.tmpl"},
+			fileMapping{"internal/auth/interceptor_test.go", "auth/interceptor_test.go.tmpl"},
+		)
+	}
+	return []fileGenerationRule{{files: files}}
+}
+
 // hasAPIType checks if the project has a specific API type
 func (g *Generator) hasAPIType(apiType api.Type) bool {
 	for _, t := range g.config.API.Types {
@@ -264,6 +910,8 @@ func (g *Generator) createDirectoryStructure() error {
 		"internal/database",
 		"internal/posts",
 		"internal/metrics",
+		"internal/reqctx",
+		"internal/logging",
 		"internal/auth",
 	}
 
@@ -272,6 +920,20 @@ func (g *Generator) createDirectoryStructure() error {
 		switch feature {
 		case config.FeaturePostHog:
 			dirs = append(dirs, "internal/posthog")
+		case config.FeatureOutbox:
+			dirs = append(dirs, "internal/outbox")
+		case config.FeatureJobs:
+			dirs = append(dirs, "internal/jobs")
+		case config.FeatureScheduler:
+			dirs = append(dirs, "internal/scheduler")
+		case config.FeatureAPIKeys:
+			dirs = append(dirs, "internal/apikeys")
+		case config.FeatureTokens:
+			dirs = append(dirs, "internal/json")
+		case config.FeatureTracing:
+			dirs = append(dirs, "internal/tracing")
+		case config.FeatureObservability:
+			dirs = append(dirs, "observability")
 		}
 	}
 
@@ -279,6 +941,9 @@ func (g *Generator) createDirectoryStructure() error {
 	for _, apiType := range g.config.API.Types {
 		if apiType == api.TypeChi {
 			dirs = append(dirs, "internal/api", "internal/json")
+			if g.config.API.OpenAPISpec != "" {
+				dirs = append(dirs, "internal/openapi")
+			}
 		} else if apiType == api.TypeGRPC {
 			dirs = append(dirs, "internal/api", "protos/posts/v1")
 		}
@@ -289,11 +954,21 @@ func (g *Generator) createDirectoryStructure() error {
 		dirs = append(dirs, "terraform")
 	}
 
-	// Add migrations directory if using PostgreSQL
-	if g.config.Database.Type == database.TypePostgres {
+	// Add migrations directory if using PostgreSQL or MySQL
+	if g.config.Database.Type == database.TypePostgres || g.config.Database.Type == database.TypeMySQL {
 		dirs = append(dirs, "migrations")
 	}
 
+	// Add sqlc query and output directories
+	if g.config.Database.Codegen == database.CodegenSQLC {
+		dirs = append(dirs, "queries", "internal/posts/postsdb")
+	}
+
+	// Add embedded migrations directory if using SQLite
+	if g.config.Database.Type == database.TypeSQLite {
+		dirs = append(dirs, "internal/database/migrations")
+	}
+
 	for _, dir := range dirs {
 		path := filepath.Join(g.config.OutputDir, dir)
 		if err := g.fs.MkdirAll(path, 0755); err != nil {
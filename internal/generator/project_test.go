@@ -3,6 +3,7 @@ package generator
 import (
 	"errors"
 	"path/filepath"
+	"strings"
 	"testing"
 	"text/template"
 
@@ -10,7 +11,10 @@ import (
 	"github.com/anmho/create-go-service/internal/generator/config"
 	"github.com/anmho/create-go-service/internal/generator/database"
 	"github.com/anmho/create-go-service/internal/generator/deployment"
+	"github.com/anmho/create-go-service/internal/generator/events"
+	"github.com/anmho/create-go-service/internal/generator/jobs"
 	"github.com/anmho/create-go-service/internal/generator/mocks"
+	"github.com/anmho/create-go-service/internal/generator/ratelimit"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -106,6 +110,8 @@ func TestCreateDirectoryStructure(t *testing.T) {
 				"/tmp/test/internal/database",
 				"/tmp/test/internal/posts",
 				"/tmp/test/internal/metrics",
+				"/tmp/test/internal/reqctx",
+				"/tmp/test/internal/logging",
 				"/tmp/test/internal/auth",
 				"/tmp/test/internal/api",
 				"/tmp/test/internal/json",
@@ -326,9 +332,12 @@ func TestGetFileGenerationRules(t *testing.T) {
 func TestGenerateAPIFilesInRules(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		name          string
-		apiType       api.Type
-		expectedFiles []string
+		name            string
+		apiType         api.Type
+		openAPISpec     string
+		protoDir        string
+		expectedFiles   []string
+		unexpectedFiles []string
 	}{
 		{
 			name:    "Chi",
@@ -337,9 +346,26 @@ func TestGenerateAPIFilesInRules(t *testing.T) {
 				"internal/api/server.go",
 				"internal/json/json.go",
 				"internal/posts/handlers.go",
+				"internal/posts/handlers_test.go",
 				"cmd/api/main.go", // Only if not gRPC
 			},
 		},
+		{
+			name:        "Chi from OpenAPI",
+			apiType:     api.TypeChi,
+			openAPISpec: "api.yaml",
+			expectedFiles: []string{
+				"internal/api/server.go",
+				"internal/json/json.go",
+				"internal/openapi/types.go",
+				"internal/openapi/server.go",
+				"internal/openapi/handlers.go",
+			},
+			unexpectedFiles: []string{
+				"internal/posts/handlers.go",
+				"internal/posts/handlers_test.go",
+			},
+		},
 		{
 			name:    "gRPC",
 			apiType: api.TypeGRPC,
@@ -351,6 +377,21 @@ func TestGenerateAPIFilesInRules(t *testing.T) {
 				"buf.gen.yaml",
 				"cmd/api/main.go",
 			},
+			unexpectedFiles: []string{
+				"internal/api/service_handlers.go",
+			},
+		},
+		{
+			name:     "gRPC from proto",
+			apiType:  api.TypeGRPC,
+			protoDir: "./protos",
+			expectedFiles: []string{
+				"internal/api/server.go",
+				"internal/api/posts_handler.go",
+				"internal/api/service_handlers.go",
+				"buf.yaml",
+				"buf.gen.yaml",
+			},
 		},
 	}
 
@@ -364,7 +405,9 @@ func TestGenerateAPIFilesInRules(t *testing.T) {
 				ModulePath:  "github.com/test/service",
 				OutputDir:   "/tmp/test",
 				API: api.Config{
-					Types: []api.Type{tt.apiType},
+					Types:       []api.Type{tt.apiType},
+					OpenAPISpec: tt.openAPISpec,
+					ProtoDir:    tt.protoDir,
 				},
 				Database: database.Config{
 					Type: database.TypeDynamoDB,
@@ -396,6 +439,11 @@ func TestGenerateAPIFilesInRules(t *testing.T) {
 					t.Errorf("expected file %s not found in generation rules", expectedFile)
 				}
 			}
+			for _, unexpectedFile := range tt.unexpectedFiles {
+				if foundFiles[unexpectedFile] {
+					t.Errorf("unexpected file %s found in generation rules", unexpectedFile)
+				}
+			}
 		})
 	}
 }
@@ -405,6 +453,8 @@ func TestGenerateDatabaseFilesInRules(t *testing.T) {
 	tests := []struct {
 		name          string
 		database      database.Type
+		codegen       database.Codegen
+		design        database.Design
 		expectedFiles []string
 	}{
 		{
@@ -420,6 +470,19 @@ func TestGenerateDatabaseFilesInRules(t *testing.T) {
 				"terraform/README.md",
 			},
 		},
+		{
+			name:     "DynamoDB single-table",
+			database: database.TypeDynamoDB,
+			design:   database.DesignSingleTable,
+			expectedFiles: []string{
+				"internal/database/dynamodb.go",
+				"internal/database/dynamodb_table.go",
+				"internal/posts/dynamodb_table.go",
+				"internal/posts/dynamodb_keys.go",
+				"internal/posts/dynamodb_keys_test.go",
+				"terraform/main.tf",
+			},
+		},
 		{
 			name:     "Postgres",
 			database: database.TypePostgres,
@@ -427,9 +490,67 @@ func TestGenerateDatabaseFilesInRules(t *testing.T) {
 				"internal/database/postgres.go",
 				"internal/posts/postgres_table.go",
 				"internal/posts/post_table_test.go",
+				"internal/posts/cursor.go",
+				"atlas.hcl",
+				"migrations/001_initial.up.sql",
+				"migrations/001_initial.down.sql",
+				"migrations/002_posts_keyset_index.up.sql",
+				"migrations/002_posts_keyset_index.down.sql",
+				"migrations/003_posts_version.up.sql",
+				"migrations/003_posts_version.down.sql",
+			},
+		},
+		{
+			name:     "Postgres with sqlc",
+			database: database.TypePostgres,
+			codegen:  database.CodegenSQLC,
+			expectedFiles: []string{
+				"internal/posts/postgres_table.go",
+				"sqlc.yaml",
+				"queries/posts.sql",
+				"internal/posts/postsdb/db.go",
+				"internal/posts/postsdb/models.go",
+				"internal/posts/postsdb/posts.sql.go",
+				".github/workflows/sqlc.yml",
+				"migrations/001_initial.up.sql",
+			},
+		},
+		{
+			name:     "MySQL",
+			database: database.TypeMySQL,
+			expectedFiles: []string{
+				"internal/database/mysql.go",
+				"internal/posts/mysql_table.go",
+				"internal/posts/post_table_test.go",
+				"internal/posts/cursor.go",
 				"atlas.hcl",
 				"migrations/001_initial.up.sql",
 				"migrations/001_initial.down.sql",
+				"migrations/002_posts_version.up.sql",
+				"migrations/002_posts_version.down.sql",
+			},
+		},
+		{
+			name:     "MongoDB",
+			database: database.TypeMongoDB,
+			expectedFiles: []string{
+				"internal/database/mongodb.go",
+				"internal/posts/mongodb_table.go",
+				"internal/posts/post_table_test.go",
+				"internal/posts/cursor.go",
+			},
+		},
+		{
+			name:     "SQLite",
+			database: database.TypeSQLite,
+			expectedFiles: []string{
+				"internal/database/sqlite.go",
+				"internal/database/migrations/001_initial.sql",
+				"internal/database/migrations/002_posts_keyset_index.sql",
+				"internal/database/migrations/003_posts_version.sql",
+				"internal/posts/sqlite_table.go",
+				"internal/posts/cursor.go",
+				"internal/posts/post_table_test.go",
 			},
 		},
 	}
@@ -447,7 +568,9 @@ func TestGenerateDatabaseFilesInRules(t *testing.T) {
 					Types: []api.Type{api.TypeChi},
 				},
 				Database: database.Config{
-					Type: tt.database,
+					Type:    tt.database,
+					Codegen: tt.codegen,
+					Design:  tt.design,
 				},
 				Deployment: deployment.Config{
 					Type: deployment.TypeFly,
@@ -522,6 +645,8 @@ func TestGeneratePostsFilesInRules(t *testing.T) {
 	expectedFiles := []string{
 		"internal/posts/post.go",
 		"internal/posts/service.go",
+		"internal/posts/instrumented_table.go",
+		"internal/posts/instrumented_table_test.go",
 		"internal/posts/converters.go",
 		"internal/posts/converters_test.go",
 		"internal/posts/dynamodb_table.go",
@@ -548,6 +673,8 @@ func TestGenerateFeatureFilesInRules(t *testing.T) {
 			features: []config.Feature{config.FeatureAuth},
 			expectedFiles: []string{
 				"internal/metrics/metrics.go", // Always generated
+				"internal/reqctx/reqctx.go",   // Always generated
+				"internal/logging/logging.go", // Always generated
 				"internal/auth/jwt.go",
 			},
 		},
@@ -556,6 +683,8 @@ func TestGenerateFeatureFilesInRules(t *testing.T) {
 			features: []config.Feature{config.FeaturePostHog},
 			expectedFiles: []string{
 				"internal/metrics/metrics.go", // Always generated
+				"internal/reqctx/reqctx.go",   // Always generated
+				"internal/logging/logging.go", // Always generated
 				"internal/posthog/posthog.go",
 			},
 		},
@@ -564,15 +693,41 @@ func TestGenerateFeatureFilesInRules(t *testing.T) {
 			features: []config.Feature{config.FeatureAuth, config.FeaturePostHog},
 			expectedFiles: []string{
 				"internal/metrics/metrics.go", // Always generated
+				"internal/reqctx/reqctx.go",   // Always generated
+				"internal/logging/logging.go", // Always generated
 				"internal/auth/jwt.go",
 				"internal/posthog/posthog.go",
 			},
 		},
+		{
+			name:     "Tracing feature",
+			features: []config.Feature{config.FeatureTracing},
+			expectedFiles: []string{
+				"internal/metrics/metrics.go", // Always generated
+				"internal/reqctx/reqctx.go",   // Always generated
+				"internal/logging/logging.go", // Always generated
+				"internal/tracing/tracing.go",
+				"internal/tracing/tracing_test.go",
+			},
+		},
+		{
+			name:     "Observability feature",
+			features: []config.Feature{config.FeatureObservability},
+			expectedFiles: []string{
+				"internal/metrics/metrics.go", // Always generated
+				"internal/reqctx/reqctx.go",   // Always generated
+				"internal/logging/logging.go", // Always generated
+				"observability/prometheus/alerts.yml",
+				"observability/grafana/dashboards/service.json",
+			},
+		},
 		{
 			name:     "No features",
 			features: []config.Feature{},
 			expectedFiles: []string{
 				"internal/metrics/metrics.go", // Always generated
+				"internal/reqctx/reqctx.go",   // Always generated
+				"internal/logging/logging.go", // Always generated
 			},
 		},
 	}
@@ -610,8 +765,12 @@ func TestGenerateFeatureFilesInRules(t *testing.T) {
 				if rule.condition == nil || rule.condition(gen) {
 					for _, file := range rule.files {
 						if filepath.Dir(file.outputPath) == "internal/metrics" ||
+							filepath.Dir(file.outputPath) == "internal/reqctx" ||
+							filepath.Dir(file.outputPath) == "internal/logging" ||
 							filepath.Dir(file.outputPath) == "internal/auth" ||
-							filepath.Dir(file.outputPath) == "internal/posthog" {
+							filepath.Dir(file.outputPath) == "internal/posthog" ||
+							filepath.Dir(file.outputPath) == "internal/tracing" ||
+							strings.HasPrefix(file.outputPath, "observability/") {
 							foundFiles[file.outputPath] = true
 						}
 					}
@@ -628,8 +787,14 @@ func TestGenerateFeatureFilesInRules(t *testing.T) {
 			// Check that unexpected files are not present
 			allFeatureFiles := []string{
 				"internal/metrics/metrics.go",
+				"internal/reqctx/reqctx.go",
+				"internal/logging/logging.go",
 				"internal/auth/jwt.go",
 				"internal/posthog/posthog.go",
+				"internal/tracing/tracing.go",
+				"internal/tracing/tracing_test.go",
+				"observability/prometheus/alerts.yml",
+				"observability/grafana/dashboards/service.json",
 			}
 			for _, file := range allFeatureFiles {
 				shouldExist := false
@@ -650,3 +815,964 @@ func TestGenerateFeatureFilesInRules(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSoftDeleteFilesInRules(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		dbType        database.Type
+		expectedFiles []string
+	}{
+		{
+			name:   "Postgres",
+			dbType: database.TypePostgres,
+			expectedFiles: []string{
+				"migrations/004_posts_soft_delete.up.sql",
+				"migrations/004_posts_soft_delete.down.sql",
+				"internal/posts/purge.go",
+				"internal/posts/purge_test.go",
+			},
+		},
+		{
+			name:   "MySQL",
+			dbType: database.TypeMySQL,
+			expectedFiles: []string{
+				"migrations/003_posts_soft_delete.up.sql",
+				"migrations/003_posts_soft_delete.down.sql",
+				"internal/posts/purge.go",
+				"internal/posts/purge_test.go",
+			},
+		},
+		{
+			name:   "MongoDB",
+			dbType: database.TypeMongoDB,
+			expectedFiles: []string{
+				"internal/posts/purge.go",
+				"internal/posts/purge_test.go",
+			},
+		},
+		{
+			name:   "SQLite",
+			dbType: database.TypeSQLite,
+			expectedFiles: []string{
+				"internal/database/migrations/004_posts_soft_delete.sql",
+				"internal/posts/purge.go",
+				"internal/posts/purge_test.go",
+			},
+		},
+		{
+			name:          "DynamoDB expires posts by TTL",
+			dbType:        database.TypeDynamoDB,
+			expectedFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    []config.Feature{config.FeatureSoftDelete},
+				API: api.Config{
+					Types: []api.Type{api.TypeChi},
+				},
+				Database: database.Config{
+					Type: tt.dbType,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			softDeleteFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						if strings.Contains(file.outputPath, "soft_delete") || strings.Contains(file.outputPath, "purge") {
+							softDeleteFiles[file.outputPath] = true
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if !softDeleteFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(softDeleteFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d soft delete files, got %v", len(tt.expectedFiles), softDeleteFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateOutboxFilesInRules(t *testing.T) {
+	t.Parallel()
+	packageFiles := []string{
+		"internal/outbox/outbox.go",
+		"internal/outbox/relay.go",
+		"internal/outbox/relay_test.go",
+		"internal/outbox/publisher.go",
+		"internal/posts/events.go",
+	}
+	tests := []struct {
+		name          string
+		dbType        database.Type
+		codegen       database.Codegen
+		expectedFiles []string
+	}{
+		{
+			name:   "Postgres",
+			dbType: database.TypePostgres,
+			expectedFiles: []string{
+				"migrations/005_outbox.up.sql",
+				"migrations/005_outbox.down.sql",
+			},
+		},
+		{
+			name:    "Postgres with sqlc",
+			dbType:  database.TypePostgres,
+			codegen: database.CodegenSQLC,
+			expectedFiles: []string{
+				"migrations/005_outbox.up.sql",
+				"migrations/005_outbox.down.sql",
+				"queries/outbox.sql",
+				"internal/posts/postsdb/outbox.sql.go",
+			},
+		},
+		{
+			name:   "MySQL",
+			dbType: database.TypeMySQL,
+			expectedFiles: []string{
+				"migrations/004_outbox.up.sql",
+				"migrations/004_outbox.down.sql",
+			},
+		},
+		{
+			name:   "SQLite",
+			dbType: database.TypeSQLite,
+			expectedFiles: []string{
+				"internal/database/migrations/005_outbox.sql",
+			},
+		},
+		{
+			name:   "DynamoDB",
+			dbType: database.TypeDynamoDB,
+			expectedFiles: []string{
+				"internal/posts/dynamodb_outbox.go",
+				"internal/posts/dynamodb_outbox_test.go",
+			},
+		},
+		{
+			name:          "MongoDB stores events in a collection",
+			dbType:        database.TypeMongoDB,
+			expectedFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    []config.Feature{config.FeatureOutbox},
+				API: api.Config{
+					Types: []api.Type{api.TypeChi},
+				},
+				Database: database.Config{
+					Type:    tt.dbType,
+					Codegen: tt.codegen,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			outboxFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						if strings.Contains(file.outputPath, "outbox") || strings.HasSuffix(file.outputPath, "events.go") {
+							outboxFiles[file.outputPath] = true
+						}
+					}
+				}
+			}
+
+			expectedFiles := append(append([]string{}, packageFiles...), tt.expectedFiles...)
+			for _, expectedFile := range expectedFiles {
+				if !outboxFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(outboxFiles) != len(expectedFiles) {
+				t.Errorf("expected %d outbox files, got %v", len(expectedFiles), outboxFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateEventBrokerFilesInRules(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		features      []config.Feature
+		eventsType    events.Type
+		expectedFiles []string
+	}{
+		{
+			name:          "NATS",
+			features:      []config.Feature{config.FeatureOutbox},
+			eventsType:    events.TypeNATS,
+			expectedFiles: []string{"internal/outbox/nats.go", "internal/outbox/nats_test.go"},
+		},
+		{
+			name:          "Kafka",
+			features:      []config.Feature{config.FeatureOutbox},
+			eventsType:    events.TypeKafka,
+			expectedFiles: []string{"internal/outbox/kafka.go", "internal/outbox/kafka_test.go"},
+		},
+		{
+			name:          "SQS",
+			features:      []config.Feature{config.FeatureOutbox},
+			eventsType:    events.TypeSQS,
+			expectedFiles: []string{"internal/outbox/sqs.go", "internal/outbox/sqs_test.go"},
+		},
+		{
+			name:          "Pub/Sub",
+			features:      []config.Feature{config.FeatureOutbox},
+			eventsType:    events.TypePubSub,
+			expectedFiles: []string{"internal/outbox/pubsub.go", "internal/outbox/pubsub_test.go"},
+		},
+		{
+			name:          "No broker logs events",
+			features:      []config.Feature{config.FeatureOutbox},
+			eventsType:    events.TypeNone,
+			expectedFiles: nil,
+		},
+		{
+			name:          "Broker without the outbox feature",
+			features:      nil,
+			eventsType:    events.TypeKafka,
+			expectedFiles: nil,
+		},
+	}
+
+	brokerFiles := []string{"nats", "kafka", "sqs", "pubsub"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    tt.features,
+				API: api.Config{
+					Types: []api.Type{api.TypeChi},
+				},
+				Database: database.Config{
+					Type: database.TypePostgres,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+				Events: events.Config{
+					Type: tt.eventsType,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			publisherFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						for _, broker := range brokerFiles {
+							if strings.HasPrefix(file.outputPath, "internal/outbox/"+broker) {
+								publisherFiles[file.outputPath] = true
+							}
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if !publisherFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(publisherFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d publisher files, got %v", len(tt.expectedFiles), publisherFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateJobsFilesInRules(t *testing.T) {
+	t.Parallel()
+	packageFiles := []string{
+		"internal/jobs/jobs.go",
+		"internal/jobs/worker.go",
+		"internal/jobs/worker_test.go",
+		"internal/jobs/memory.go",
+		"internal/jobs/queue_test.go",
+		"internal/cli/jobs.go",
+	}
+	postgresFiles := []string{
+		"internal/jobs/postgres.go",
+		"internal/jobs/postgres_test.go",
+		"migrations/006_jobs.up.sql",
+		"migrations/006_jobs.down.sql",
+	}
+	redisFiles := []string{
+		"internal/jobs/redis.go",
+		"internal/jobs/redis_test.go",
+	}
+
+	tests := []struct {
+		name          string
+		features      []config.Feature
+		dbType        database.Type
+		jobsType      jobs.Type
+		expectedFiles []string
+	}{
+		{
+			name:          "Postgres database defaults to the Postgres queue",
+			features:      []config.Feature{config.FeatureJobs},
+			dbType:        database.TypePostgres,
+			expectedFiles: append(append([]string{}, packageFiles...), postgresFiles...),
+		},
+		{
+			name:          "Other databases default to the Redis queue",
+			features:      []config.Feature{config.FeatureJobs},
+			dbType:        database.TypeMongoDB,
+			expectedFiles: append(append([]string{}, packageFiles...), redisFiles...),
+		},
+		{
+			name:          "Redis queue with Postgres",
+			features:      []config.Feature{config.FeatureJobs},
+			dbType:        database.TypePostgres,
+			jobsType:      jobs.TypeRedis,
+			expectedFiles: append(append([]string{}, packageFiles...), redisFiles...),
+		},
+		{
+			name:          "Queue without the jobs feature",
+			features:      nil,
+			dbType:        database.TypePostgres,
+			jobsType:      jobs.TypeRedis,
+			expectedFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    tt.features,
+				API: api.Config{
+					Types: []api.Type{api.TypeChi},
+				},
+				Database: database.Config{
+					Type: tt.dbType,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+				Jobs: jobs.Config{
+					Type: tt.jobsType,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			jobsFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						if strings.Contains(file.outputPath, "jobs") {
+							jobsFiles[file.outputPath] = true
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if !jobsFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(jobsFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d jobs files, got %v", len(tt.expectedFiles), jobsFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateAuthFilesInRules(t *testing.T) {
+	t.Parallel()
+	packageFiles := []string{
+		"internal/auth/jwt.go",
+		"internal/auth/jwt_test.go",
+		"internal/auth/jwks.go",
+		"internal/auth/jwks_test.go",
+		"internal/auth/policy.go",
+		"internal/auth/policy_test.go",
+		"internal/auth/principal.go",
+		"internal/posts/policy.go",
+		"internal/posts/policy_test.go",
+	}
+
+	tests := []struct {
+		name          string
+		apiType       api.Type
+		expectedFiles []string
+	}{
+		{
+			name:    "Chi middleware",
+			apiType: api.TypeChi,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/auth/middleware.go",
+				"internal/auth/middleware_test.go",
+			),
+		},
+		{
+			name:    "Connect interceptor",
+			apiType: api.TypeGRPC,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/auth/interceptor.go",
+				"internal/auth/interceptor_test.go",
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    []config.Feature{config.FeatureAuth},
+				API: api.Config{
+					Types: []api.Type{tt.apiType},
+				},
+				Database: database.Config{
+					Type: database.TypePostgres,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			authFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						if strings.HasPrefix(file.outputPath, "internal/auth/") || strings.HasPrefix(file.outputPath, "internal/posts/policy") {
+							authFiles[file.outputPath] = true
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if !authFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(authFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d auth files, got %v", len(tt.expectedFiles), authFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateSchedulerFilesInRules(t *testing.T) {
+	t.Parallel()
+	packageFiles := []string{
+		"internal/scheduler/scheduler.go",
+		"internal/scheduler/scheduler_test.go",
+		"internal/scheduler/elector.go",
+	}
+
+	tests := []struct {
+		name          string
+		features      []config.Feature
+		dbType        database.Type
+		expectedFiles []string
+	}{
+		{
+			name:     "Postgres advisory lock",
+			features: []config.Feature{config.FeatureScheduler},
+			dbType:   database.TypePostgres,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/scheduler/postgres.go",
+				"internal/scheduler/postgres_test.go",
+				"internal/scheduler/elector_test.go",
+			),
+		},
+		{
+			name:     "DynamoDB lease item",
+			features: []config.Feature{config.FeatureScheduler},
+			dbType:   database.TypeDynamoDB,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/scheduler/dynamodb.go",
+				"internal/scheduler/dynamodb_test.go",
+				"internal/scheduler/elector_test.go",
+			),
+		},
+		{
+			name:          "SQLite uses the local elector",
+			features:      []config.Feature{config.FeatureScheduler},
+			dbType:        database.TypeSQLite,
+			expectedFiles: packageFiles,
+		},
+		{
+			name:          "Without the scheduler feature",
+			features:      nil,
+			dbType:        database.TypePostgres,
+			expectedFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    tt.features,
+				API: api.Config{
+					Types: []api.Type{api.TypeChi},
+				},
+				Database: database.Config{
+					Type: tt.dbType,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			schedulerFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						if strings.HasPrefix(file.outputPath, "internal/scheduler/") {
+							schedulerFiles[file.outputPath] = true
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if !schedulerFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(schedulerFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d scheduler files, got %v", len(tt.expectedFiles), schedulerFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateAPIKeysFilesInRules(t *testing.T) {
+	t.Parallel()
+	packageFiles := []string{
+		"internal/apikeys/apikeys.go",
+		"internal/apikeys/apikeys_test.go",
+		"internal/apikeys/limiter.go",
+		"internal/apikeys/limiter_test.go",
+		"internal/apikeys/memory.go",
+		"internal/apikeys/store_test.go",
+		"internal/cli/keys.go",
+	}
+
+	tests := []struct {
+		name          string
+		features      []config.Feature
+		apiTypes      []api.Type
+		dbType        database.Type
+		rateLimitType ratelimit.Type
+		expectedFiles []string
+	}{
+		{
+			name:     "Chi with Postgres and in-memory rate limits",
+			features: []config.Feature{config.FeatureAPIKeys},
+			apiTypes: []api.Type{api.TypeChi},
+			dbType:   database.TypePostgres,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/apikeys/postgres.go",
+				"internal/apikeys/postgres_test.go",
+				"internal/apikeys/middleware.go",
+				"internal/apikeys/middleware_test.go",
+				"migrations/007_api_keys.up.sql",
+				"migrations/007_api_keys.down.sql",
+			),
+		},
+		{
+			name:          "gRPC with SQLite and Redis rate limits",
+			features:      []config.Feature{config.FeatureAPIKeys},
+			apiTypes:      []api.Type{api.TypeGRPC},
+			dbType:        database.TypeSQLite,
+			rateLimitType: ratelimit.TypeRedis,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/apikeys/sqlite.go",
+				"internal/apikeys/sqlite_test.go",
+				"internal/apikeys/interceptor.go",
+				"internal/apikeys/interceptor_test.go",
+				"internal/apikeys/redis_limiter.go",
+				"internal/apikeys/redis_limiter_test.go",
+				"internal/database/migrations/006_api_keys.sql",
+			),
+		},
+		{
+			name:     "Chi and gRPC with DynamoDB",
+			features: []config.Feature{config.FeatureAPIKeys},
+			apiTypes: []api.Type{api.TypeChi, api.TypeGRPC},
+			dbType:   database.TypeDynamoDB,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/apikeys/dynamodb.go",
+				"internal/apikeys/dynamodb_test.go",
+				"internal/apikeys/middleware.go",
+				"internal/apikeys/middleware_test.go",
+				"internal/apikeys/interceptor.go",
+				"internal/apikeys/interceptor_test.go",
+			),
+		},
+		{
+			name:          "Without the apikeys feature",
+			features:      nil,
+			apiTypes:      []api.Type{api.TypeChi},
+			dbType:        database.TypePostgres,
+			expectedFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    tt.features,
+				API: api.Config{
+					Types: tt.apiTypes,
+				},
+				Database: database.Config{
+					Type: tt.dbType,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+				RateLimit: ratelimit.Config{
+					Type: tt.rateLimitType,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			apiKeysFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						if strings.HasPrefix(file.outputPath, "internal/apikeys/") ||
+							strings.Contains(file.outputPath, "api_keys") ||
+							file.outputPath == "internal/cli/keys.go" {
+							apiKeysFiles[file.outputPath] = true
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if !apiKeysFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(apiKeysFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d apikeys files, got %v", len(tt.expectedFiles), apiKeysFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateCredentialFilesInRules(t *testing.T) {
+	t.Parallel()
+	packageFiles := []string{
+		"internal/credential/credential.go",
+		"internal/credential/credential_test.go",
+	}
+
+	tests := []struct {
+		name          string
+		features      []config.Feature
+		dbType        database.Type
+		expectedFiles []string
+	}{
+		{
+			name:     "API keys with Postgres",
+			features: []config.Feature{config.FeatureAPIKeys},
+			dbType:   database.TypePostgres,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/database/databasetest/postgres.go",
+			),
+		},
+		{
+			name:     "Tokens with MongoDB",
+			features: []config.Feature{config.FeatureAuth, config.FeatureTokens},
+			dbType:   database.TypeMongoDB,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/database/databasetest/mongodb.go",
+			),
+		},
+		{
+			name:     "API keys and tokens with DynamoDB",
+			features: []config.Feature{config.FeatureAuth, config.FeatureAPIKeys, config.FeatureTokens},
+			dbType:   database.TypeDynamoDB,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/database/databasetest/dynamodb.go",
+			),
+		},
+		{
+			name:          "Without API keys or tokens",
+			features:      []config.Feature{config.FeatureAuth},
+			dbType:        database.TypeSQLite,
+			expectedFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    tt.features,
+				API: api.Config{
+					Types: []api.Type{api.TypeChi},
+				},
+				Database: database.Config{
+					Type: tt.dbType,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			// Counted, so files shared by both features are generated once
+			credentialFiles := make(map[string]int)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						if strings.HasPrefix(file.outputPath, "internal/credential/") ||
+							strings.HasPrefix(file.outputPath, "internal/database/databasetest/") {
+							credentialFiles[file.outputPath]++
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if credentialFiles[expectedFile] != 1 {
+					t.Errorf("expected file %s once in generation rules, got %d", expectedFile, credentialFiles[expectedFile])
+				}
+			}
+			if len(credentialFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d credential files, got %v", len(tt.expectedFiles), credentialFiles)
+			}
+		})
+	}
+}
+
+func TestGenerateTokensFilesInRules(t *testing.T) {
+	t.Parallel()
+	packageFiles := []string{
+		"internal/auth/handlers.go",
+		"internal/auth/handlers_test.go",
+		"internal/auth/issuer.go",
+		"internal/auth/issuer_test.go",
+		"internal/auth/refresh.go",
+		"internal/auth/refresh_memory.go",
+		"internal/auth/refresh_store_test.go",
+		"internal/auth/signing.go",
+		"internal/auth/signing_test.go",
+		"internal/cli/tokens.go",
+		// The token endpoints write JSON with it; chi projects generate it anyway
+		"internal/json/json.go",
+	}
+
+	tests := []struct {
+		name          string
+		features      []config.Feature
+		apiTypes      []api.Type
+		dbType        database.Type
+		expectedFiles []string
+	}{
+		{
+			name:     "Chi with Postgres",
+			features: []config.Feature{config.FeatureAuth, config.FeatureTokens},
+			apiTypes: []api.Type{api.TypeChi},
+			dbType:   database.TypePostgres,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/auth/refresh_postgres.go",
+				"internal/auth/refresh_postgres_test.go",
+				"migrations/008_refresh_tokens.up.sql",
+				"migrations/008_refresh_tokens.down.sql",
+			),
+		},
+		{
+			name:     "gRPC with MySQL",
+			features: []config.Feature{config.FeatureAuth, config.FeatureTokens},
+			apiTypes: []api.Type{api.TypeGRPC},
+			dbType:   database.TypeMySQL,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/auth/refresh_mysql.go",
+				"internal/auth/refresh_mysql_test.go",
+				"migrations/006_refresh_tokens.up.sql",
+				"migrations/006_refresh_tokens.down.sql",
+			),
+		},
+		{
+			name:     "gRPC with SQLite",
+			features: []config.Feature{config.FeatureAuth, config.FeatureTokens},
+			apiTypes: []api.Type{api.TypeGRPC},
+			dbType:   database.TypeSQLite,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/auth/refresh_sqlite.go",
+				"internal/auth/refresh_sqlite_test.go",
+				"internal/database/migrations/007_refresh_tokens.sql",
+			),
+		},
+		{
+			name:     "Chi with DynamoDB",
+			features: []config.Feature{config.FeatureAuth, config.FeatureTokens},
+			apiTypes: []api.Type{api.TypeChi},
+			dbType:   database.TypeDynamoDB,
+			expectedFiles: append(append([]string{}, packageFiles...),
+				"internal/auth/refresh_dynamodb.go",
+				"internal/auth/refresh_dynamodb_test.go",
+			),
+		},
+		{
+			name:          "Without the tokens feature",
+			features:      []config.Feature{config.FeatureAuth},
+			apiTypes:      []api.Type{api.TypeGRPC},
+			dbType:        database.TypeMongoDB,
+			expectedFiles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockFS := mocks.NewFileSystem(t)
+			mockLoader := NewMockTemplateLoader()
+
+			config := config.ProjectConfig{
+				ProjectName: "test-service",
+				ModulePath:  "github.com/test/service",
+				OutputDir:   "/tmp/test",
+				Features:    tt.features,
+				API: api.Config{
+					Types: tt.apiTypes,
+				},
+				Database: database.Config{
+					Type: tt.dbType,
+				},
+				Deployment: deployment.Config{
+					Type: deployment.TypeFly,
+				},
+			}
+			gen := NewGeneratorWithDeps(config, mockFS, mockLoader)
+
+			// Mock MkdirAll for .github/workflows (called by deployment condition)
+			mockFS.On("MkdirAll", filepath.Join("/tmp/test", ".github", "workflows"), mock.Anything).Return(nil)
+
+			tokensFiles := make(map[string]bool)
+			for _, rule := range gen.getFileGenerationRules() {
+				if rule.condition == nil || rule.condition(gen) {
+					for _, file := range rule.files {
+						name := strings.TrimPrefix(file.outputPath, "internal/auth/")
+						if strings.HasPrefix(name, "handlers") || strings.HasPrefix(name, "issuer") ||
+							strings.HasPrefix(name, "refresh") || strings.HasPrefix(name, "signing") ||
+							strings.Contains(file.outputPath, "refresh_tokens") ||
+							file.outputPath == "internal/cli/tokens.go" || file.outputPath == "internal/json/json.go" {
+							tokensFiles[file.outputPath] = true
+						}
+					}
+				}
+			}
+
+			for _, expectedFile := range tt.expectedFiles {
+				if !tokensFiles[expectedFile] {
+					t.Errorf("expected file %s not found in generation rules", expectedFile)
+				}
+			}
+			if len(tokensFiles) != len(tt.expectedFiles) {
+				t.Errorf("expected %d tokens files, got %v", len(tt.expectedFiles), tokensFiles)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+// Package proto discovers the services in an existing .proto tree and resolves
+// the Go and Connect packages that buf generates for them, so the grpc
+// templates can register every service and scaffold a handler stub for each.
+package proto
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// File is a .proto file discovered in the source tree
+type File struct {
+	Path     string // Slash-separated path relative to the proto root
+	Package  string
+	Messages []string // Message names relative to the package, nested ones dotted (Outer.Inner)
+	Services []*Service
+}
+
+// Service is a service declared in a .proto file
+type Service struct {
+	Name          string // Proto service name, e.g. InvoiceService
+	FullName      string // Fully-qualified name, e.g. billing.v1.InvoiceService
+	Handler       string // Name of the generated stub struct
+	ConnectAlias  string // Import alias of the generated Connect package
+	ConnectImport string
+	Methods       []*Method
+}
+
+// Method is an RPC of a service
+type Method struct {
+	Name            string
+	Request         string // Qualified Go type, e.g. billingv1.GetInvoiceRequest
+	Response        string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// Import is a Go import used by the generated stubs
+type Import struct {
+	Alias string
+	Path  string
+}
+
+// Set is the resolved view of every service in a proto tree
+type Set struct {
+	Files          []*File
+	Services       []*Service
+	Imports        []Import // Message packages used by the stubs
+	ConnectImports []Import // Connect packages of the services
+}
+
+// wellKnownTypes maps google.protobuf messages to their Go packages
+var wellKnownTypes = map[string]Import{
+	"google.protobuf.Any":         {"anypb", "google.golang.org/protobuf/types/known/anypb"},
+	"google.protobuf.Duration":    {"durationpb", "google.golang.org/protobuf/types/known/durationpb"},
+	"google.protobuf.Empty":       {"emptypb", "google.golang.org/protobuf/types/known/emptypb"},
+	"google.protobuf.FieldMask":   {"fieldmaskpb", "google.golang.org/protobuf/types/known/fieldmaskpb"},
+	"google.protobuf.Struct":      {"structpb", "google.golang.org/protobuf/types/known/structpb"},
+	"google.protobuf.Value":       {"structpb", "google.golang.org/protobuf/types/known/structpb"},
+	"google.protobuf.Timestamp":   {"timestamppb", "google.golang.org/protobuf/types/known/timestamppb"},
+	"google.protobuf.BoolValue":   {"wrapperspb", "google.golang.org/protobuf/types/known/wrapperspb"},
+	"google.protobuf.BytesValue":  {"wrapperspb", "google.golang.org/protobuf/types/known/wrapperspb"},
+	"google.protobuf.DoubleValue": {"wrapperspb", "google.golang.org/protobuf/types/known/wrapperspb"},
+	"google.protobuf.Int64Value":  {"wrapperspb", "google.golang.org/protobuf/types/known/wrapperspb"},
+	"google.protobuf.StringValue": {"wrapperspb", "google.golang.org/protobuf/types/known/wrapperspb"},
+}
+
+var (
+	packagePattern = regexp.MustCompile(`\bpackage\s+([\w.]+)\s*;`)
+	servicePattern = regexp.MustCompile(`\bservice\s+(\w+)\s*\{`)
+	messagePattern = regexp.MustCompile(`\b(message|enum)\s+(\w+)\s*\{`)
+	rpcPattern     = regexp.MustCompile(`\brpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+	versionPattern = regexp.MustCompile(`^v\d+((alpha|beta)\d*)?$`)
+)
+
+// Parse extracts the package and services declared in a .proto file.
+// Message types are left as written in the file until Resolve is called.
+func Parse(filePath string, src []byte) (*File, error) {
+	text := sanitize(string(src))
+
+	f := &File{Path: filePath}
+	if m := packagePattern.FindStringSubmatch(text); m != nil {
+		f.Package = m[1]
+	}
+
+	// Track enclosing messages by their closing brace to name nested messages
+	type scope struct {
+		name string
+		end  int
+	}
+	var scopes []scope
+	for _, loc := range messagePattern.FindAllStringSubmatchIndex(text, -1) {
+		for len(scopes) > 0 && scopes[len(scopes)-1].end < loc[0] {
+			scopes = scopes[:len(scopes)-1]
+		}
+		name := text[loc[4]:loc[5]]
+		if len(scopes) > 0 {
+			name = scopes[len(scopes)-1].name + "." + name
+		}
+		if text[loc[2]:loc[3]] == "message" {
+			f.Messages = append(f.Messages, name)
+		}
+		scopes = append(scopes, scope{name: name, end: matchingBrace(text, loc[1]-1)})
+	}
+
+	for _, loc := range servicePattern.FindAllStringSubmatchIndex(text, -1) {
+		name := text[loc[2]:loc[3]]
+		end := matchingBrace(text, loc[1]-1)
+		if end < 0 {
+			return nil, fmt.Errorf("%s: unterminated service %s", filePath, name)
+		}
+		if f.Package == "" {
+			return nil, fmt.Errorf("%s: service %s requires a package declaration", filePath, name)
+		}
+
+		s := &Service{Name: name, FullName: f.Package + "." + name}
+		for _, m := range rpcPattern.FindAllStringSubmatch(text[loc[1]:end], -1) {
+			s.Methods = append(s.Methods, &Method{
+				Name:            m[1],
+				ClientStreaming: m[2] != "",
+				Request:         m[3],
+				ServerStreaming: m[4] != "",
+				Response:        m[5],
+			})
+		}
+		f.Services = append(f.Services, s)
+	}
+
+	return f, nil
+}
+
+// Resolve computes the Go packages buf generates for each file under goPackagePrefix
+// (managed mode with paths=source_relative) and qualifies every request and response type
+func Resolve(files []*File, goPackagePrefix string) (*Set, error) {
+	set := &Set{Files: files}
+
+	// Go package of each proto package, and the package of each fully-qualified message
+	packages := make(map[string]Import)
+	messages := make(map[string]string)
+	for _, f := range files {
+		if f.Package == "" {
+			continue
+		}
+		for _, m := range f.Messages {
+			messages[f.Package+"."+m] = f.Package
+		}
+		imp := Import{
+			Alias: GoPackageName(f.Package),
+			Path:  goPackagePrefix + "/" + path.Dir(f.Path),
+		}
+		if existing, ok := packages[f.Package]; ok && existing.Path != imp.Path {
+			return nil, fmt.Errorf("package %s is split across directories %s and %s", f.Package, existing.Path, imp.Path)
+		}
+		packages[f.Package] = imp
+	}
+
+	aliases := newAliases()
+	handlers := make(map[string]string)
+	connectImports := make(map[string]string)
+	for _, f := range files {
+		for _, s := range f.Services {
+			s.Handler = s.Name + "Handler"
+			if other, ok := handlers[s.Handler]; ok {
+				return nil, fmt.Errorf("services %s and %s would both generate %s", other, s.FullName, s.Handler)
+			}
+			handlers[s.Handler] = s.FullName
+
+			own := packages[f.Package]
+			s.ConnectImport = own.Path + "/" + own.Alias + "connect"
+			s.ConnectAlias = aliases.add(own.Alias+"connect", s.ConnectImport)
+			connectImports[s.ConnectImport] = s.ConnectAlias
+
+			for _, m := range s.Methods {
+				var err error
+				if m.Request, err = resolveType(m.Request, f.Package, messages, packages, aliases); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", s.FullName, m.Name, err)
+				}
+				if m.Response, err = resolveType(m.Response, f.Package, messages, packages, aliases); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", s.FullName, m.Name, err)
+				}
+			}
+			set.Services = append(set.Services, s)
+		}
+	}
+
+	set.Imports = aliases.messageImports()
+	for importPath, alias := range connectImports {
+		set.ConnectImports = append(set.ConnectImports, Import{Alias: alias, Path: importPath})
+	}
+	sort.Slice(set.ConnectImports, func(i, j int) bool { return set.ConnectImports[i].Path < set.ConnectImports[j].Path })
+	return set, nil
+}
+
+// GoPackageName returns the Go package name buf's managed mode derives from a
+// proto package: the last two components when the last one is a version (billing.v1 -> billingv1)
+func GoPackageName(protoPackage string) string {
+	parts := strings.Split(protoPackage, ".")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && versionPattern.MatchString(name) {
+		name = parts[len(parts)-2] + name
+	}
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// resolveType converts a message reference into a qualified Go type, following
+// protobuf scoping: relative names are looked up from the file's package outwards
+func resolveType(name, filePackage string, messages map[string]string, packages map[string]Import, aliases *aliases) (string, error) {
+	qualified := strings.TrimPrefix(name, ".")
+	if imp, ok := wellKnownTypes[qualified]; ok {
+		alias := aliases.addMessage(imp.Alias, imp.Path)
+		return alias + "." + qualified[strings.LastIndex(qualified, ".")+1:], nil
+	}
+
+	candidates := []string{qualified}
+	if !strings.HasPrefix(name, ".") {
+		candidates = nil
+		for scope := filePackage; scope != ""; scope = parentScope(scope) {
+			candidates = append(candidates, scope+"."+qualified)
+		}
+		candidates = append(candidates, qualified)
+	}
+	for _, candidate := range candidates {
+		pkg, ok := messages[candidate]
+		if !ok {
+			continue
+		}
+		imp := packages[pkg]
+		alias := aliases.addMessage(imp.Alias, imp.Path)
+		// Nested messages are generated as Outer_Inner
+		message := strings.ReplaceAll(strings.TrimPrefix(candidate, pkg+"."), ".", "_")
+		return alias + "." + message, nil
+	}
+	return "", fmt.Errorf("cannot resolve message type %s (only messages in the proto tree and google.protobuf well-known types are supported)", name)
+}
+
+// parentScope returns the enclosing scope of a dotted name ("" at the top level)
+func parentScope(scope string) string {
+	if i := strings.LastIndex(scope, "."); i >= 0 {
+		return scope[:i]
+	}
+	return ""
+}
+
+// aliases assigns unique import aliases to Go packages
+type aliases struct {
+	byPath   map[string]string
+	used     map[string]bool
+	messages map[string]bool // Paths of message packages
+}
+
+func newAliases() *aliases {
+	return &aliases{
+		byPath:   make(map[string]string),
+		used:     make(map[string]bool),
+		messages: make(map[string]bool),
+	}
+}
+
+// add returns the alias for an import path, suffixing a number when the preferred alias is taken
+func (a *aliases) add(preferred, importPath string) string {
+	if alias, ok := a.byPath[importPath]; ok {
+		return alias
+	}
+	alias := preferred
+	for i := 2; a.used[alias]; i++ {
+		alias = fmt.Sprintf("%s%d", preferred, i)
+	}
+	a.byPath[importPath] = alias
+	a.used[alias] = true
+	return alias
+}
+
+func (a *aliases) addMessage(preferred, importPath string) string {
+	a.messages[importPath] = true
+	return a.add(preferred, importPath)
+}
+
+// messageImports returns the message packages sorted by import path
+func (a *aliases) messageImports() []Import {
+	var imports []Import
+	for importPath := range a.messages {
+		imports = append(imports, Import{Alias: a.byPath[importPath], Path: importPath})
+	}
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path < imports[j].Path })
+	return imports
+}
+
+// sanitize blanks out comments and string literal contents, keeping offsets,
+// so braces inside option strings or comments don't confuse the parser
+func sanitize(src string) string {
+	out := []byte(src)
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			for ; i < len(out) && !(out[i] == '*' && i+1 < len(out) && out[i+1] == '/'); i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			if i+1 < len(out) {
+				out[i], out[i+1] = ' ', ' '
+				i++
+			}
+		case out[i] == '"' || out[i] == '\'':
+			quote := out[i]
+			for i++; i < len(out) && out[i] != quote; i++ {
+				if out[i] == '\\' && i+1 < len(out) {
+					out[i] = ' '
+					i++
+				}
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+		}
+	}
+	return string(out)
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or -1
+func matchingBrace(text string, open int) int {
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,187 @@
+package proto
+
+import (
+	"strings"
+	"testing"
+)
+
+const billingProto = `
+syntax = "proto3";
+
+package acme.billing.v1;
+
+import "google/protobuf/empty.proto";
+import "common/v1/money.proto";
+
+/* service Ignored { rpc Nope(A) returns (B); } */
+service InvoiceService {
+  // GetInvoice returns { an invoice }
+  rpc GetInvoice(GetInvoiceRequest) returns (Invoice) {
+    option (google.api.http) = {get: "/v1/invoices/{id}"};
+  }
+  rpc DeleteInvoice(GetInvoiceRequest) returns (google.protobuf.Empty);
+  rpc WatchInvoices(google.protobuf.Empty) returns (stream Invoice);
+  rpc UploadLines(stream Invoice.Line) returns (common.v1.Money);
+  rpc Chat(stream Invoice) returns (stream Invoice);
+}
+
+message GetInvoiceRequest { string id = 1; }
+
+message Invoice {
+  message Line { string sku = 1; }
+  string id = 1;
+  repeated Line lines = 2;
+}
+`
+
+const moneyProto = `
+syntax = "proto3";
+package acme.common.v1;
+message Money { int64 units = 1; }
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	f, err := Parse("billing/v1/billing.proto", []byte(billingProto))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if f.Package != "acme.billing.v1" {
+		t.Errorf("expected package acme.billing.v1, got %s", f.Package)
+	}
+	if strings.Join(f.Messages, ",") != "GetInvoiceRequest,Invoice,Invoice.Line" {
+		t.Errorf("unexpected messages: %v", f.Messages)
+	}
+	if len(f.Services) != 1 || f.Services[0].FullName != "acme.billing.v1.InvoiceService" {
+		t.Fatalf("expected only InvoiceService (commented-out services are ignored), got %+v", f.Services)
+	}
+
+	methods := f.Services[0].Methods
+	if len(methods) != 5 {
+		t.Fatalf("expected 5 methods, got %d", len(methods))
+	}
+	if methods[2].ClientStreaming || !methods[2].ServerStreaming {
+		t.Errorf("expected WatchInvoices to be server streaming, got %+v", methods[2])
+	}
+	if !methods[4].ClientStreaming || !methods[4].ServerStreaming {
+		t.Errorf("expected Chat to be bidi streaming, got %+v", methods[4])
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+	billing, err := Parse("billing/v1/billing.proto", []byte(billingProto))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	money, err := Parse("common/v1/money.proto", []byte(moneyProto))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	set, err := Resolve([]*File{billing, money}, "github.com/acme/svc/protos/gen")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	s := set.Services[0]
+	if s.Handler != "InvoiceServiceHandler" || s.ConnectAlias != "billingv1connect" ||
+		s.ConnectImport != "github.com/acme/svc/protos/gen/billing/v1/billingv1connect" {
+		t.Errorf("unexpected service: %+v", s)
+	}
+
+	tests := []struct {
+		method   string
+		request  string
+		response string
+	}{
+		{"GetInvoice", "billingv1.GetInvoiceRequest", "billingv1.Invoice"},
+		{"DeleteInvoice", "billingv1.GetInvoiceRequest", "emptypb.Empty"},
+		{"WatchInvoices", "emptypb.Empty", "billingv1.Invoice"},
+		{"UploadLines", "billingv1.Invoice_Line", "commonv1.Money"},
+	}
+	for i, tt := range tests {
+		m := s.Methods[i]
+		if m.Name != tt.method || m.Request != tt.request || m.Response != tt.response {
+			t.Errorf("expected %s(%s) returns (%s), got %s(%s) returns (%s)",
+				tt.method, tt.request, tt.response, m.Name, m.Request, m.Response)
+		}
+	}
+
+	var imports []string
+	for _, imp := range set.Imports {
+		imports = append(imports, imp.Alias+" "+imp.Path)
+	}
+	expected := []string{
+		"billingv1 github.com/acme/svc/protos/gen/billing/v1",
+		"commonv1 github.com/acme/svc/protos/gen/common/v1",
+		"emptypb google.golang.org/protobuf/types/known/emptypb",
+	}
+	if strings.Join(imports, ";") != strings.Join(expected, ";") {
+		t.Errorf("expected imports %v, got %v", expected, imports)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "unknown message",
+			files: map[string]string{
+				"a/v1/a.proto": "package a.v1; service A { rpc Get(Missing) returns (Missing); }",
+			},
+			wantErr: "cannot resolve message type Missing",
+		},
+		{
+			name: "duplicate service name",
+			files: map[string]string{
+				"a/v1/a.proto": "package a.v1; service Search { rpc Get(M) returns (M); } message M {}",
+				"b/v1/b.proto": "package b.v1; service Search { rpc Get(M) returns (M); } message M {}",
+			},
+			wantErr: "would both generate SearchHandler",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var files []*File
+			for _, filePath := range []string{"a/v1/a.proto", "b/v1/b.proto"} {
+				src, ok := tt.files[filePath]
+				if !ok {
+					continue
+				}
+				f, err := Parse(filePath, []byte(src))
+				if err != nil {
+					t.Fatalf("Parse failed: %v", err)
+				}
+				files = append(files, f)
+			}
+
+			_, err := Resolve(files, "example.com/gen")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGoPackageName(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"acme.billing.v1":      "billingv1",
+		"acme.billing.v1beta1": "billingv1beta1",
+		"acme.billing":         "billing",
+		"user_service.v2":      "userservicev2",
+	}
+	for pkg, expected := range tests {
+		if got := GoPackageName(pkg); got != expected {
+			t.Errorf("GoPackageName(%q) = %q, expected %q", pkg, got, expected)
+		}
+	}
+}
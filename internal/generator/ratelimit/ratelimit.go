@@ -0,0 +1,14 @@
+package ratelimit
+
+// Type represents the store API key rate limit buckets are kept in
+type Type string
+
+const (
+	TypeMemory Type = ""      // Token buckets in each instance's memory, so each instance enforces the limit on its own
+	TypeRedis  Type = "redis" // Token buckets in Redis, shared by every instance
+)
+
+// Config holds API key rate limiting configuration
+type Config struct {
+	Type Type
+}
@@ -5,6 +5,9 @@ import (
 	"github.com/anmho/create-go-service/internal/generator/config"
 	"github.com/anmho/create-go-service/internal/generator/database"
 	"github.com/anmho/create-go-service/internal/generator/deployment"
+	"github.com/anmho/create-go-service/internal/generator/events"
+	"github.com/anmho/create-go-service/internal/generator/jobs"
+	"github.com/anmho/create-go-service/internal/generator/ratelimit"
 )
 
 func (g *Generator) getTemplateData() map[string]interface{} {
@@ -13,6 +16,14 @@ func (g *Generator) getTemplateData() map[string]interface{} {
 	hasHotReload := true
 	hasAuth := false
 	hasPostHog := false
+	hasSoftDelete := false
+	hasOutbox := false
+	hasJobs := false
+	hasScheduler := false
+	hasAPIKeys := false
+	hasTokens := false
+	hasTracing := false
+	hasObservability := false
 
 	for _, feature := range g.config.Features {
 		switch feature {
@@ -20,9 +31,37 @@ func (g *Generator) getTemplateData() map[string]interface{} {
 			hasAuth = true
 		case config.FeaturePostHog:
 			hasPostHog = true
+		case config.FeatureSoftDelete:
+			hasSoftDelete = true
+		case config.FeatureOutbox:
+			hasOutbox = true
+		case config.FeatureJobs:
+			hasJobs = true
+		case config.FeatureScheduler:
+			hasScheduler = true
+		case config.FeatureAPIKeys:
+			hasAPIKeys = true
+		case config.FeatureTokens:
+			hasTokens = true
+		case config.FeatureTracing:
+			hasTracing = true
+		case config.FeatureObservability:
+			hasObservability = true
 		}
 	}
 
+	// Issued tokens are validated by the auth middleware and interceptor, so issuance needs auth
+	hasTokens = hasTokens && hasAuth
+
+	// API key rate limits are kept in Redis when configured, and otherwise in each instance's memory
+	hasRedisRateLimit := hasAPIKeys && g.config.RateLimit.Type == ratelimit.TypeRedis
+
+	// Events are published to a broker only through the outbox relay
+	eventBroker := events.TypeNone
+	if hasOutbox {
+		eventBroker = g.config.Events.Type
+	}
+
 	// Determine API types
 	hasChi := false
 	hasHuma := false
@@ -39,25 +78,52 @@ func (g *Generator) getTemplateData() map[string]interface{} {
 	}
 
 	return map[string]interface{}{
-		"ProjectName":   g.config.ProjectName,
-		"ModulePath":    g.config.ModulePath,
-		"OutputDir":     g.config.OutputDir,
-		"APITypes":      g.config.API.Types,
-		"Database":      string(g.config.Database.Type),
-		"Features":      g.config.Features,
-		"Deployment":    string(g.config.Deployment.Type),
-		"HasChi":        hasChi,
-		"HasHuma":       hasHuma,
-		"HasGRPC":       hasGRPC,
-		"HasDynamoDB":   g.config.Database.Type == database.TypeDynamoDB,
-		"HasPostgres":   g.config.Database.Type == database.TypePostgres,
-		"HasMetrics":    hasMetrics,
-		"HasPostHog":    hasPostHog,
-		"HasAuth":       hasAuth,
-		"HasHotReload":  hasHotReload,
-		"HasFly":        g.config.Deployment.Type == deployment.TypeFly,
-		"JWTSecret":     g.config.Auth.JWTSecret,
-		"PostHogAPIKey": g.config.PostHog.APIKey,
-		"PostHogHost":   g.config.PostHog.Host,
+		"ProjectName":       g.config.ProjectName,
+		"ModulePath":        g.config.ModulePath,
+		"OutputDir":         g.config.OutputDir,
+		"APITypes":          g.config.API.Types,
+		"Database":          string(g.config.Database.Type),
+		"Features":          g.config.Features,
+		"Deployment":        string(g.config.Deployment.Type),
+		"HasChi":            hasChi,
+		"HasHuma":           hasHuma,
+		"HasGRPC":           hasGRPC,
+		"HasDynamoDB":       g.config.Database.Type == database.TypeDynamoDB,
+		"HasPostgres":       g.config.Database.Type == database.TypePostgres,
+		"HasMySQL":          g.config.Database.Type == database.TypeMySQL,
+		"HasMongoDB":        g.config.Database.Type == database.TypeMongoDB,
+		"HasSQLC":           g.config.Database.Codegen == database.CodegenSQLC,
+		"HasSingleTable":    g.config.Database.Type == database.TypeDynamoDB && g.config.Database.Design == database.DesignSingleTable,
+		"HasSQLite":         g.config.Database.Type == database.TypeSQLite,
+		"HasMetrics":        hasMetrics,
+		"HasPostHog":        hasPostHog,
+		"HasAuth":           hasAuth,
+		"HasSoftDelete":     hasSoftDelete,
+		"HasOutbox":         hasOutbox,
+		"HasEventBroker":    eventBroker != events.TypeNone,
+		"HasNATS":           eventBroker == events.TypeNATS,
+		"HasKafka":          eventBroker == events.TypeKafka,
+		"HasSQS":            eventBroker == events.TypeSQS,
+		"HasPubSub":         eventBroker == events.TypePubSub,
+		"HasJobs":           hasJobs,
+		"HasPostgresJobs":   g.jobQueue() == jobs.TypePostgres,
+		"HasRedisJobs":      g.jobQueue() == jobs.TypeRedis,
+		"HasScheduler":      hasScheduler,
+		"HasAPIKeys":        hasAPIKeys,
+		"HasRedisRateLimit": hasRedisRateLimit,
+		"HasRedis":          g.jobQueue() == jobs.TypeRedis || hasRedisRateLimit,
+		"HasTokens":         hasTokens,
+		"HasTracing":        hasTracing,
+		"HasObservability":  hasObservability,
+		"HasHotReload":      hasHotReload,
+		"HasFly":            g.config.Deployment.Type == deployment.TypeFly,
+		"HasOpenAPI":        g.openAPI != nil,
+		"OpenAPI":           g.openAPI,
+		"HasProtoTree":      g.protos != nil,
+		"ProtoTree":         g.protos,
+		"PostKeys":          g.postKeys,
+		"JWTSecret":         g.config.Auth.JWTSecret,
+		"PostHogAPIKey":     g.config.PostHog.APIKey,
+		"PostHogHost":       g.config.PostHog.Host,
 	}
 }
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -83,11 +84,22 @@ func NewModel() *Model {
 	databaseOptions := []string{
 		"DynamoDB",
 		"PostgreSQL",
+		"MySQL",
+		"MongoDB",
+		"SQLite",
 	}
 
 	featureOptions := []string{
 		"JWT Auth (Supabase/Clerk)",
 		"PostHog (Event Tracking)",
+		"Soft Delete (restore + purge)",
+		"Outbox (post events)",
+		"Jobs (background queue)",
+		"Scheduler (cron tasks)",
+		"API Keys (rate limited)",
+		"Token Issuance (own identity provider)",
+		"Tracing (OpenTelemetry)",
+		"Observability (Grafana dashboards, alerts)",
 	}
 
 	deploymentOptions := []string{
@@ -202,7 +214,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				hasAuth := false
 				hasPostHog := false
 				for _, s := range selectedFeatures {
-					if strings.Contains(s, "JWT Auth") {
+					// Token issuance needs auth, so it prompts for the JWT secret too
+					if strings.Contains(s, "JWT Auth") || strings.Contains(s, "Token Issuance") {
 						hasAuth = true
 					}
 					if strings.Contains(s, "PostHog") {
@@ -302,6 +315,12 @@ func (m *Model) generate() tea.Cmd {
 			dbType = database.TypeDynamoDB
 		} else if strings.Contains(selectedDB, "PostgreSQL") {
 			dbType = database.TypePostgres
+		} else if strings.Contains(selectedDB, "MySQL") {
+			dbType = database.TypeMySQL
+		} else if strings.Contains(selectedDB, "MongoDB") {
+			dbType = database.TypeMongoDB
+		} else if strings.Contains(selectedDB, "SQLite") {
+			dbType = database.TypeSQLite
 		}
 
 		// Map features (metrics and hot reload are always enabled, not in Feature enum)
@@ -314,6 +333,34 @@ func (m *Model) generate() tea.Cmd {
 			if strings.Contains(s, "PostHog") {
 				features = append(features, config.FeaturePostHog)
 			}
+			if strings.Contains(s, "Soft Delete") {
+				features = append(features, config.FeatureSoftDelete)
+			}
+			if strings.Contains(s, "Outbox") {
+				features = append(features, config.FeatureOutbox)
+			}
+			if strings.Contains(s, "Jobs") {
+				features = append(features, config.FeatureJobs)
+			}
+			if strings.Contains(s, "Scheduler") {
+				features = append(features, config.FeatureScheduler)
+			}
+			if strings.Contains(s, "API Keys") {
+				features = append(features, config.FeatureAPIKeys)
+			}
+			if strings.Contains(s, "Token Issuance") {
+				features = append(features, config.FeatureTokens)
+			}
+			if strings.Contains(s, "Tracing") {
+				features = append(features, config.FeatureTracing)
+			}
+			if strings.Contains(s, "Observability") {
+				features = append(features, config.FeatureObservability)
+			}
+		}
+		// Token issuance signs the tokens the auth middleware validates
+		if slices.Contains(features, config.FeatureTokens) && !slices.Contains(features, config.FeatureAuth) {
+			features = append(features, config.FeatureAuth)
 		}
 
 		cfg := config.ProjectConfig{
@@ -425,7 +472,7 @@ Go microservice with:
 
   • REST API (Chi or Huma)
   • gRPC with ConnectRPC
-  • Database integration (DynamoDB or PostgreSQL)
+  • Database integration (DynamoDB, PostgreSQL, MySQL, MongoDB or SQLite)
   • Metrics instrumentation
   • Deployment configs (Fly.io)
   • Hot reload with wgo`)
@@ -625,7 +672,7 @@ func (m *Model) renderComplete() string {
 
 	// Add database-specific steps
 	databaseSelected := m.databaseSelect.GetSelected()
-	if strings.Contains(databaseSelected, "PostgreSQL") {
+	if strings.Contains(databaseSelected, "PostgreSQL") || strings.Contains(databaseSelected, "MySQL") {
 		nextStepsList = append(nextStepsList, "make atlas-init  # Initialize migrations")
 		nextStepsList = append(nextStepsList, "make migrate      # Run migrations")
 	} else if strings.Contains(databaseSelected, "DynamoDB") {